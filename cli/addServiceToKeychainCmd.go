@@ -30,7 +30,7 @@ func GetAddServiceToKeychainCmd() *cobra.Command {
 		},
 	}
 
-	addServiceToKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	addServiceToKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
 	addServiceToKeychainCmd.Flags().String("access-seed", "", "Access Seed")
 	addServiceToKeychainCmd.Flags().String("service-name", "", "Service Name")
 	addServiceToKeychainCmd.Flags().String("derivation-path", "", "Derivation Path")
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/config"
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// enumCompletions gives the accepted values of the flags, by the type of their value
+var enumCompletions = map[string][]string{
+	"EndpointCLI":        {"local\thttp://localhost:4000", "testnet\thttps://testnet.archethic.net", "mainnet\thttps://mainnet.archethic.net"},
+	"CurveCLI":           {"ED25519", "P256", "SECP256K1"},
+	"HashAlgoCLI":        {"SHA256", "SHA512", "SHA3_256", "SHA3_512", "BLAKE2B"},
+	"TransactionTypeCLI": {"keychain_access", "keychain", "transfer", "hosting", "token", "data", "contract", "code_proposal", "code_approval"},
+	"EncodingCLI":        {"hex", "base64"},
+	"ContentEncodingCLI": {"raw", "gzip", "base64", "gzip+base64"},
+}
+
+// flagAliases maps the former spelling of the flags to their current name
+var flagAliases = map[string]string{
+	"serviceName": "service-name",
+}
+
+// NormalizeFlagName accepts the former spelling of the flags, such as --serviceName for --service-name
+func NormalizeFlagName(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if alias, ok := flagAliases[name]; ok {
+		name = alias
+	}
+	return pflag.NormalizedName(name)
+}
+
+// RegisterCompletions adds the completion of the flags' values to the command and its sub commands:
+// the values of the enums and of the themes, and the services of the cached keychains, the profiles and the contacts of the configuration file
+func RegisterCompletions(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		var completion func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+		switch {
+		case flag.Value.Type() == "OutputFormatCLI":
+			completion = staticCompletion(outputFormats(flag)...)
+		case enumCompletions[flag.Value.Type()] != nil:
+			completion = staticCompletion(enumCompletions[flag.Value.Type()]...)
+		case flag.Name == "service-name" || flag.Name == "service":
+			completion = completeServices
+		case flag.Name == "theme":
+			completion = staticCompletion(constants.ThemeNames()...)
+		case flag.Name == "profile":
+			completion = completeProfiles
+		case flag.Name == "address" || flag.Name == "recipients" || flag.Name == "to":
+			completion = completeContacts
+		default:
+			return
+		}
+		// the only error is a completion already registered, for a persistent flag of a parent command
+		cmd.RegisterFlagCompletionFunc(flag.Name, completion)
+	})
+	for _, subCmd := range cmd.Commands() {
+		RegisterCompletions(subCmd)
+	}
+}
+
+func staticCompletion(values ...string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// outputFormats returns the output formats supported by the command, as listed by the usage of its flag
+func outputFormats(flag *pflag.Flag) []string {
+	formats := []string{string(TableFormat), string(JSONFormat)}
+	if strings.Contains(flag.Usage, string(CSVFormat)) {
+		formats = append(formats, string(CSVFormat))
+	}
+	return formats
+}
+
+// completeServices returns the services of the keychains accessed previously, the keychain can't be read without its seed
+func completeServices(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return tuiutils.CachedServiceNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var profiles []string
+	for name, profile := range cfg.Profiles {
+		profiles = append(profiles, fmt.Sprintf("%s\t%s", name, profile.Endpoint))
+	}
+	sort.Strings(profiles)
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContacts returns the addresses of the contacts, described by their name
+func completeContacts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var addresses []string
+	for name, contact := range cfg.Contacts {
+		if contact.Address != "" {
+			addresses = append(addresses, fmt.Sprintf("%s\t%s", contact.Address, name))
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// enumValues lists the accepted values of the configuration fields which are enums
+var enumValues = map[string][]string{
+	"elliptic_curve":   {"ED25519", "P256", "SECP256K1"},
+	"content_encoding": {"raw", "gzip", "base64", "gzip+base64"},
+	"transaction_type": {"keychain_access", "keychain", "transfer", "hosting", "token", "data", "contract", "code_proposal", "code_approval"},
+}
+
+func GetConfigSchemaCmd() *cobra.Command {
+	configSchemaCmd := &cobra.Command{
+		Use:   "config-schema",
+		Short: "Print the JSON Schema of the transaction configuration file",
+		Run: func(cmd *cobra.Command, args []string) {
+			schema := jsonSchema(reflect.TypeOf(SendTransactionData{}))
+			schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+			schema["title"] = "Archethic CLI transaction configuration"
+			schemaBytes, err := json.MarshalIndent(schema, "", "  ")
+			cobra.CheckErr(err)
+			fmt.Println(string(schemaBytes))
+		},
+	}
+	return configSchemaCmd
+}
+
+// jsonSchema describes the type using its yaml tags for the property names and its description tags
+func jsonSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			property := jsonSchema(field.Type)
+			if description := field.Tag.Get("description"); description != "" {
+				property["description"] = description
+			}
+			if values, ok := enumValues[name]; ok {
+				property["enum"] = values
+			}
+			properties[name] = property
+			if options != "omitempty" {
+				required = append(required, name)
+			}
+		}
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": jsonSchema(t.Elem()),
+		}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	}
+	return map[string]interface{}{}
+}
@@ -34,7 +34,7 @@ func GetCreateKeychainCmd() *cobra.Command {
 			fmt.Println(string(jsonData))
 		},
 	}
-	createKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	createKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
 	createKeychainCmd.Flags().String("access-seed", "", "Access Seed")
 	createKeychainCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
 	createKeychainCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
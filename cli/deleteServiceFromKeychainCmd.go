@@ -22,7 +22,7 @@ func GetDeleteServiceFromKeychainCmd() *cobra.Command {
 			fmt.Println(feedback)
 		},
 	}
-	deleteServiceFromKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	deleteServiceFromKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
 	deleteServiceFromKeychainCmd.Flags().String("access-seed", "", "Access Seed")
 	deleteServiceFromKeychainCmd.Flags().String("service-name", "", "Service Name")
 	deleteServiceFromKeychainCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+func GetDeriveKeypairCmd() *cobra.Command {
+	deriveKeypairCmd := &cobra.Command{
+		Use:   "derive-keypair",
+		Short: "Derive keypairs (address and public key)",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "seed", "")
+			cobra.CheckErr(err)
+			seedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "seed", "")
+			cobra.CheckErr(err)
+
+			curve, err := ellipticCurve.GetCurve()
+			cobra.CheckErr(err)
+			hashAlgo, err := hashAlgo.GetHashAlgo()
+			cobra.CheckErr(err)
+
+			from, to, err := getIndexRange(cmd)
+			cobra.CheckErr(err)
+
+			// reverse lookup: only display the keypair matching the address
+			if cmd.Flags().Changed("lookup") {
+				lookupIndex, err := lookupAddressIndex(cmd, seedBytes, curve, hashAlgo)
+				cobra.CheckErr(err)
+				from, to = lookupIndex, lookupIndex
+			}
+
+			keypairs, err := tuiutils.DeriveKeypairs(seedBytes, from, to, curve, hashAlgo)
+			cobra.CheckErr(err)
+			err = printKeypairs(os.Stdout, keypairs, outputFormat)
+			cobra.CheckErr(err)
+		},
+	}
+
+	deriveKeypairCmd.Flags().String("seed", "", "Seed")
+	deriveKeypairCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
+	deriveKeypairCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	deriveKeypairCmd.MarkFlagsMutuallyExclusive("seed", "ssh")
+	deriveKeypairCmd.MarkFlagsMutuallyExclusive("seed", "ssh-path")
+	deriveKeypairCmd.Flags().Int("index", 0, "Index")
+	setupIndexRangeFlags(deriveKeypairCmd)
+	deriveKeypairCmd.Flags().Var(&hashAlgo, "hash-algorithm", "Hash Algorithm (SHA256|SHA512|SHA3_256|SHA3_512|BLAKE2B)")
+	deriveKeypairCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
+	return deriveKeypairCmd
+}
+
+func setupIndexRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("from", 0, "First index of the range to derive")
+	cmd.Flags().Int("to", 0, "Last index of the range to derive (default to the first index)")
+	cmd.Flags().String("lookup", "", "Address to look up: scan the indexes to find the one at which it was derived")
+	cmd.Flags().Int("max-index", 1000, "Last index scanned by the address lookup")
+	cmd.Flags().Var(&outputFormat, "output", "Output format (table|json|csv)")
+	cmd.MarkFlagsMutuallyExclusive("index", "from")
+	cmd.MarkFlagsMutuallyExclusive("index", "to")
+	cmd.MarkFlagsMutuallyExclusive("lookup", "from")
+	cmd.MarkFlagsMutuallyExclusive("lookup", "to")
+}
+
+// getIndexRange returns the range of indexes to derive based on the index, from and to flags
+func getIndexRange(cmd *cobra.Command) (uint32, uint32, error) {
+	if !cmd.Flags().Changed("from") && !cmd.Flags().Changed("to") {
+		index, err := getIndexFlag(cmd, "index")
+		return index, index, err
+	}
+	from, err := getIndexFlag(cmd, "from")
+	if err != nil {
+		return 0, 0, err
+	}
+	to := from
+	if cmd.Flags().Changed("to") {
+		to, err = getIndexFlag(cmd, "to")
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return from, to, tuiutils.CheckIndexRange(from, to)
+}
+
+// getIndexFlag returns the value of an index flag, which must fit the 32 bits of the indexes of the chains
+func getIndexFlag(cmd *cobra.Command, name string) (uint32, error) {
+	index, _ := cmd.Flags().GetInt(name)
+	if index < 0 || uint64(index) > math.MaxUint32 {
+		return 0, fmt.Errorf("--%s must be between 0 and %d", name, uint32(math.MaxUint32))
+	}
+	return uint32(index), nil
+}
+
+func lookupAddressIndex(cmd *cobra.Command, seed []byte, curve archethic.Curve, hashAlgo archethic.HashAlgo) (uint32, error) {
+	lookup, _ := cmd.Flags().GetString("lookup")
+	maxIndex, err := getIndexFlag(cmd, "max-index")
+	if err != nil {
+		return 0, err
+	}
+	address, err := hex.DecodeString(lookup)
+	if err != nil {
+		return 0, errors.New("invalid address to look up")
+	}
+	return tuiutils.FindAddressIndex(seed, address, maxIndex, curve, hashAlgo)
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/hex"
 	"fmt"
+	"os"
 
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
@@ -24,6 +25,26 @@ func GetGenerateAddressCmd() *cobra.Command {
 			cobra.CheckErr(err)
 			hashAlgo, err := hashAlgo.GetHashAlgo()
 			cobra.CheckErr(err)
+
+			// reverse lookup: find the index at which the address was derived
+			if cmd.Flags().Changed("lookup") {
+				lookupIndex, err := lookupAddressIndex(cmd, seedBytes, curve, hashAlgo)
+				cobra.CheckErr(err)
+				fmt.Println(lookupIndex)
+				return
+			}
+
+			// range of addresses
+			if cmd.Flags().Changed("from") || cmd.Flags().Changed("to") {
+				from, to, err := getIndexRange(cmd)
+				cobra.CheckErr(err)
+				keypairs, err := tuiutils.DeriveKeypairs(seedBytes, from, to, curve, hashAlgo)
+				cobra.CheckErr(err)
+				err = printKeypairs(os.Stdout, keypairs, outputFormat)
+				cobra.CheckErr(err)
+				return
+			}
+
 			address, err := archethic.DeriveAddress(seedBytes, uint32(index), curve, hashAlgo)
 			cobra.CheckErr(err)
 			fmt.Println(hex.EncodeToString(address))
@@ -36,6 +57,7 @@ func GetGenerateAddressCmd() *cobra.Command {
 	generateAddressCmd.MarkFlagsMutuallyExclusive("seed", "ssh")
 	generateAddressCmd.MarkFlagsMutuallyExclusive("seed", "ssh-path")
 	generateAddressCmd.Flags().Int("index", 0, "Index")
+	setupIndexRangeFlags(generateAddressCmd)
 	generateAddressCmd.Flags().Var(&hashAlgo, "hash-algorithm", "Hash Algorithm (SHA256|SHA512|SHA3_256|SHA3_512|BLAKE2B)")
 	generateAddressCmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
 	return generateAddressCmd
@@ -24,7 +24,7 @@ func GetKeychainCmd() *cobra.Command {
 			fmt.Printf("%s\n", jsonServices)
 		},
 	}
-	getKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	getKeychainCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
 	getKeychainCmd.Flags().String("access-seed", "", "Access Seed")
 	getKeychainCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
 	getKeychainCmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+func GetProposeCodeCmd() *cobra.Command {
+	proposeCodeCmd := &cobra.Command{
+		Use:   "propose-code",
+		Short: "Send a code proposal built from a unified diff of the node's code",
+		Run: func(cmd *cobra.Command, args []string) {
+			diffPath, _ := cmd.Flags().GetString("diff")
+			version, _ := cmd.Flags().GetString("version")
+			description, _ := cmd.Flags().GetString("description")
+			diff, err := os.ReadFile(diffPath)
+			cobra.CheckErr(err)
+			content, err := tuiutils.BuildProposalContent(description, version, string(diff))
+			cobra.CheckErr(err)
+
+			transaction := archethic.NewTransaction(archethic.CodeProposalType)
+			transaction.SetContent([]byte(content))
+			sendGovernanceTransaction(cmd, transaction)
+		},
+	}
+	setupSignerFlags(proposeCodeCmd)
+	proposeCodeCmd.Flags().String("diff", "", "The file location of the unified diff (git diff) of the changes")
+	proposeCodeCmd.Flags().String("version", "", "The version of the node set by the changes (MAJOR.MINOR.PATCH)")
+	proposeCodeCmd.Flags().String("description", "", "The description of the changes")
+	setupMaxFeeFlag(proposeCodeCmd)
+	proposeCodeCmd.MarkFlagRequired("diff")
+	proposeCodeCmd.MarkFlagRequired("version")
+	proposeCodeCmd.MarkFlagRequired("description")
+	return proposeCodeCmd
+}
+
+func GetApproveCodeCmd() *cobra.Command {
+	approveCodeCmd := &cobra.Command{
+		Use:   "approve-code",
+		Short: "Review a code proposal and send its approval",
+		Run: func(cmd *cobra.Command, args []string) {
+			applyProfileEndpoint(cmd)
+			proposalAddress, _ := cmd.Flags().GetString("proposal")
+			proposalBytes, err := hex.DecodeString(proposalAddress)
+			cobra.CheckErr(err)
+			proposal, err := tuiutils.GetProposal(endpoint.String(), proposalAddress)
+			cobra.CheckErr(err)
+
+			fmt.Printf("Proposal %s\nVersion: %s\nDescription: %s\n\n%s\n\n", proposal.Address, proposal.Version, proposal.Description, proposal.Changes)
+			yes, _ := cmd.Flags().GetBool("yes")
+			if !yes {
+				confirmed, err := confirm(os.Stdin, "Send the approval of this proposal?")
+				cobra.CheckErr(err)
+				if !confirmed {
+					cobra.CheckErr(errors.New("approval not sent"))
+				}
+			}
+
+			transaction := archethic.NewTransaction(archethic.CodeApprovalType)
+			transaction.AddRecipient(proposalBytes)
+			sendGovernanceTransaction(cmd, transaction)
+		},
+	}
+	setupSignerFlags(approveCodeCmd)
+	approveCodeCmd.Flags().String("proposal", "", "The address of the code proposal")
+	approveCodeCmd.Flags().Bool("yes", false, "Send the approval without asking for a confirmation")
+	setupMaxFeeFlag(approveCodeCmd)
+	approveCodeCmd.MarkFlagRequired("proposal")
+	return approveCodeCmd
+}
+
+func GetListProposalsCmd() *cobra.Command {
+	listProposalsCmd := &cobra.Command{
+		Use:   "list-proposals",
+		Short: "List the open code proposals and their number of approvals, or open one of them",
+		Run: func(cmd *cobra.Command, args []string) {
+			applyProfileEndpoint(cmd)
+			proposalAddress, _ := cmd.Flags().GetString("proposal")
+			if proposalAddress != "" {
+				proposal, err := tuiutils.OpenProposal(endpoint.String(), proposalAddress)
+				cobra.CheckErr(err)
+				err = printProposal(os.Stdout, proposal, outputFormat)
+				cobra.CheckErr(err)
+				return
+			}
+			all, _ := cmd.Flags().GetBool("all")
+			proposals, err := tuiutils.ListProposals(endpoint.String(), all)
+			cobra.CheckErr(err)
+			err = printProposals(os.Stdout, proposals, outputFormat)
+			cobra.CheckErr(err)
+		},
+	}
+	listProposalsCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
+	listProposalsCmd.Flags().String("profile", "", "Profile of the configuration file providing the default endpoint")
+	listProposalsCmd.Flags().String("proposal", "", "The address of a code proposal to display with its version, status, description and changes")
+	listProposalsCmd.Flags().Bool("all", false, "List the applied proposals too, not only the open ones")
+	listProposalsCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	return listProposalsCmd
+}
+
+// sendGovernanceTransaction signs the transaction with the chain given by the signer flags and sends it
+func sendGovernanceTransaction(cmd *cobra.Command, transaction *archethic.TransactionBuilder) {
+	applyProfileEndpoint(cmd)
+	err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+	cobra.CheckErr(err)
+	accessSeed, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+	cobra.CheckErr(err)
+	curve, err := ellipticCurve.GetCurve()
+	cobra.CheckErr(err)
+	maxFee, err := getMaxFee(cmd)
+	cobra.CheckErr(err)
+	index, _ := cmd.Flags().GetInt("index")
+	serviceName, _ := cmd.Flags().GetString("service-name")
+
+	secretKey := make([]byte, 32)
+	rand.Read(secretKey)
+	runTransactionAction(cmd, transaction, secretKey, curve, accessSeed, index, serviceName, sendAction(maxFee, false, cmd.Flags().Changed("index")))
+}
+
+// confirm asks a yes/no question, the default answer being no
+func confirm(r io.Reader, question string) (bool, error) {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func printProposals(w io.Writer, proposals []tuiutils.ProposalSummary, format OutputFormatCLI) error {
+	if format == JSONFormat {
+		jsonData, err := json.Marshal(proposals)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+		return nil
+	}
+
+	tabWriter := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tabWriter, "ADDRESS\tDATE\tVERSION\tSTATUS\tAPPROVALS")
+	for _, proposal := range proposals {
+		fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\t%d\n", proposal.Address, time.Unix(proposal.Timestamp, 0).UTC().Format(time.RFC3339), proposal.Version, proposalStatus(proposal.Open), proposal.Approvals)
+	}
+	return tabWriter.Flush()
+}
+
+func printProposal(w io.Writer, proposal tuiutils.Proposal, format OutputFormatCLI) error {
+	if format == JSONFormat {
+		jsonData, err := json.Marshal(proposal)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+		return nil
+	}
+
+	fmt.Fprintf(w, "Proposal %s\nVersion: %s\nStatus: %s\nApprovals: %d\nDescription: %s\n\n%s\n", proposal.Address, proposal.Version, proposalStatus(proposal.Open), proposal.Approvals, proposal.Description, proposal.Changes)
+	return nil
+}
+
+// proposalStatus is the status of a proposal: open while its version is greater than the version of the node
+func proposalStatus(open bool) string {
+	if open {
+		return "open"
+	}
+	return "applied"
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+func GetJournalCmd() *cobra.Command {
+	journalCmd := &cobra.Command{
+		Use:   "journal",
+		Short: "List, show and export the local journal of the transactions sent by the CLI and the TUI",
+	}
+	journalCmd.AddCommand(getJournalListCmd())
+	journalCmd.AddCommand(getJournalShowCmd())
+	journalCmd.AddCommand(getJournalExportCmd())
+	return journalCmd
+}
+
+func getJournalListCmd() *cobra.Command {
+	journalListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the transactions of the journal, the most recent first",
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, err := tuiutils.ReadJournal()
+			cobra.CheckErr(err)
+			entries, err = filterJournal(cmd, entries)
+			cobra.CheckErr(err)
+			// the most recent first
+			for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+				entries[i], entries[j] = entries[j], entries[i]
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			if limit > 0 && len(entries) > limit {
+				entries = entries[:limit]
+			}
+			err = printJournal(os.Stdout, entries, outputFormat)
+			cobra.CheckErr(err)
+		},
+	}
+	setupJournalFilterFlags(journalListCmd)
+	journalListCmd.Flags().Int("limit", 0, "Maximum number of transactions listed (0 for no limit)")
+	journalListCmd.Flags().Var(&outputFormat, "output", "Output format (table|json|csv)")
+	return journalListCmd
+}
+
+func getJournalShowCmd() *cobra.Command {
+	journalShowCmd := &cobra.Command{
+		Use:   "show [address]",
+		Short: "Show the entries of the journal of a transaction",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, err := tuiutils.FindJournalEntries(args[0])
+			cobra.CheckErr(err)
+			if len(entries) == 0 {
+				cobra.CheckErr(fmt.Errorf("no transaction %s in the journal", args[0]))
+			}
+			if outputFormat == JSONFormat {
+				jsonData, err := json.Marshal(entries)
+				cobra.CheckErr(err)
+				fmt.Println(string(jsonData))
+				return
+			}
+			for _, entry := range entries {
+				printJournalEntry(os.Stdout, entry)
+				fmt.Println()
+			}
+		},
+	}
+	journalShowCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	return journalShowCmd
+}
+
+func getJournalExportCmd() *cobra.Command {
+	journalExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the journal in JSON, or in CSV with a line per transfer",
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, err := tuiutils.ReadJournal()
+			cobra.CheckErr(err)
+			entries, err = filterJournal(cmd, entries)
+			cobra.CheckErr(err)
+			format := JSONFormat
+			if csvExport, _ := cmd.Flags().GetBool("csv"); csvExport {
+				format = CSVFormat
+			}
+			w := io.Writer(os.Stdout)
+			if file, _ := cmd.Flags().GetString("file"); file != "" {
+				f, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+				cobra.CheckErr(err)
+				defer f.Close()
+				w = f
+			}
+			err = printJournal(w, entries, format)
+			cobra.CheckErr(err)
+		},
+	}
+	setupJournalFilterFlags(journalExportCmd)
+	journalExportCmd.Flags().Bool("csv", false, "Export in CSV instead of JSON")
+	journalExportCmd.Flags().String("file", "", "File written, instead of the standard output")
+	return journalExportCmd
+}
+
+func setupJournalFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("since", "", "Only the transactions sent since this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().String("until", "", "Only the transactions sent before this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().String("status", "", "Only the transactions with this status (pending|sent|confirmed|failed)")
+}
+
+// filterJournal keeps the entries matching the --since, --until and --status flags
+func filterJournal(cmd *cobra.Command, entries []tuiutils.JournalEntry) ([]tuiutils.JournalEntry, error) {
+	since, err := parseJournalDate(cmd, "since")
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseJournalDate(cmd, "until")
+	if err != nil {
+		return nil, err
+	}
+	status, _ := cmd.Flags().GetString("status")
+	var filtered []tuiutils.JournalEntry
+	for _, entry := range entries {
+		if !since.IsZero() && entry.Timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !entry.Timestamp.Before(until) {
+			continue
+		}
+		if status != "" && !strings.EqualFold(entry.Status, status) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered, nil
+}
+
+func parseJournalDate(cmd *cobra.Command, flag string) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(flag)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date for --%s: %s", flag, value)
+	}
+	return date, nil
+}
+
+func printJournal(w io.Writer, entries []tuiutils.JournalEntry, format OutputFormatCLI) error {
+	switch format {
+	case JSONFormat:
+		jsonData, err := json.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+	case CSVFormat:
+		// a line per transfer, so the amounts can be summed by a spreadsheet
+		csvWriter := csv.NewWriter(w)
+		csvWriter.Write([]string{"timestamp", "operator", "host", "command", "endpoint", "address", "type", "fee", "status", "error", "to", "amount", "token_address", "token_id"})
+		for _, entry := range entries {
+			line := []string{entry.Timestamp.Format(time.RFC3339), entry.Operator, entry.Host, entry.Command, entry.Endpoint, entry.Address, entry.Type, tuiutils.FormatAmount(entry.Fee), entry.Status, entry.Error}
+			if len(entry.Transfers) == 0 {
+				csvWriter.Write(append(line, "", "", "", ""))
+			}
+			for _, transfer := range entry.Transfers {
+				tokenId := ""
+				if transfer.TokenAddress != "" {
+					tokenId = strconv.Itoa(transfer.TokenId)
+				}
+				csvWriter.Write(append(line, transfer.To, tuiutils.FormatAmount(transfer.Amount), transfer.TokenAddress, tokenId))
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	default:
+		tabWriter := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tabWriter, "TIMESTAMP\tOPERATOR\tCOMMAND\tTYPE\tSTATUS\tFEE\tTRANSFERS\tADDRESS")
+		for _, entry := range entries {
+			fmt.Fprintf(tabWriter, "%s\t%s@%s\t%s\t%s\t%s\t%s\t%d\t%s\n", entry.Timestamp.Local().Format(time.DateTime), entry.Operator, entry.Host, entry.Command, entry.Type, entry.Status, tuiutils.FormatAmount(entry.Fee), len(entry.Transfers), entry.Address)
+		}
+		return tabWriter.Flush()
+	}
+	return nil
+}
+
+func printJournalEntry(w io.Writer, entry tuiutils.JournalEntry) {
+	fmt.Fprintf(w, "Address:   %s\n", entry.Address)
+	fmt.Fprintf(w, "Type:      %s\n", entry.Type)
+	if entry.Update {
+		fmt.Fprintf(w, "Updated:   %s\n", entry.Timestamp.Local().Format(time.DateTime))
+	} else {
+		fmt.Fprintf(w, "Sent at:   %s\n", entry.Timestamp.Local().Format(time.DateTime))
+	}
+	fmt.Fprintf(w, "Operator:  %s@%s\n", entry.Operator, entry.Host)
+	fmt.Fprintf(w, "Command:   %s\n", entry.Command)
+	fmt.Fprintf(w, "Endpoint:  %s\n", entry.Endpoint)
+	fmt.Fprintf(w, "Fee:       %s UCO\n", tuiutils.FormatAmount(entry.Fee))
+	fmt.Fprintf(w, "Status:    %s\n", entry.Status)
+	if entry.Error != "" {
+		fmt.Fprintf(w, "Error:     %s\n", entry.Error)
+	}
+	for _, transfer := range entry.Transfers {
+		if transfer.TokenAddress == "" {
+			fmt.Fprintf(w, "Transfer:  %s UCO to %s\n", tuiutils.FormatAmount(transfer.Amount), transfer.To)
+		} else {
+			fmt.Fprintf(w, "Transfer:  %s of token %s (id %d) to %s\n", tuiutils.FormatAmount(transfer.Amount), transfer.TokenAddress, transfer.TokenId, transfer.To)
+		}
+	}
+}
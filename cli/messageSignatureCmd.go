@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+type SignedMessage struct {
+	Message     string `json:"message,omitempty"`
+	Address     string `json:"address,omitempty"`
+	PublicKey   string `json:"public_key"`
+	Signature   string `json:"signature"`
+	Encoding    string `json:"encoding"`
+	Curve       string `json:"curve"`
+	Index       int    `json:"index"`
+	ServiceName string `json:"service_name,omitempty"`
+}
+
+type VerifiedMessage struct {
+	Valid     bool   `json:"valid"`
+	PublicKey string `json:"public_key"`
+	Address   string `json:"address,omitempty"`
+}
+
+func GetSignMessageCmd() *cobra.Command {
+	signMessageCmd := &cobra.Command{
+		Use:   "sign-message",
+		Short: "Sign a message or a file",
+		Run: func(cmd *cobra.Command, args []string) {
+			index, _ := cmd.Flags().GetInt("index")
+			serviceName, _ := cmd.Flags().GetString("service-name")
+			message, data, err := readMessage(cmd)
+			cobra.CheckErr(err)
+			err = validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			accessSeedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			curve, err := ellipticCurve.GetCurve()
+			cobra.CheckErr(err)
+			if index < 0 {
+				cobra.CheckErr(errors.New("the index must be positive"))
+			}
+
+			keypairs, err := tuiutils.CandidateKeypairs(endpoint.String(), accessSeedBytes, curve, serviceName, index)
+			cobra.CheckErr(err)
+			keypair := keypairs[0]
+
+			signature, err := archethic.Sign(keypair.PrivateKey, data)
+			cobra.CheckErr(err)
+
+			signedMessage := SignedMessage{
+				Message:     message,
+				PublicKey:   strings.ToUpper(hex.EncodeToString(keypair.PublicKey)),
+				Signature:   encoding.Encode(signature),
+				Encoding:    encoding.String(),
+				Curve:       tuiutils.GetCurveName(curve),
+				Index:       index,
+				ServiceName: serviceName,
+			}
+			// the address can only be derived from the seed outside of the service mode.
+			// It is the address of the transaction signed by the key of the index, whose previous public key
+			// is the signer's key: the address of the chain at index+1, as read by verify-message --address
+			if serviceName == "" {
+				address, err := archethic.DeriveAddress(accessSeedBytes, uint32(index+1), curve, archethic.SHA256)
+				cobra.CheckErr(err)
+				signedMessage.Address = strings.ToUpper(hex.EncodeToString(address))
+			}
+
+			jsonData, err := json.Marshal(signedMessage)
+			cobra.CheckErr(err)
+			fmt.Println(string(jsonData))
+		},
+	}
+	signMessageCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order, only used with a service")
+	signMessageCmd.Flags().String("message", "", "Message to sign")
+	signMessageCmd.Flags().String("file", "", "The file location of the data to sign")
+	setupSeedFlags(signMessageCmd)
+	signMessageCmd.Flags().Int("index", 0, "Index of the key used to sign")
+	signMessageCmd.Flags().String("service-name", "", "Service Name (to sign with the key of a keychain's service)")
+	signMessageCmd.Flags().Var(&encoding, "encoding", "Signature encoding (hex|base64)")
+	signMessageCmd.MarkFlagsMutuallyExclusive("message", "file")
+	return signMessageCmd
+}
+
+func GetVerifyMessageCmd() *cobra.Command {
+	verifyMessageCmd := &cobra.Command{
+		Use:   "verify-message",
+		Short: "Verify the signature of a message or a file",
+		Run: func(cmd *cobra.Command, args []string) {
+			signatureStr, _ := cmd.Flags().GetString("signature")
+			publicKeyHex, _ := cmd.Flags().GetString("public-key")
+			address, _ := cmd.Flags().GetString("address")
+			_, data, err := readMessage(cmd)
+			cobra.CheckErr(err)
+
+			if publicKeyHex == "" && address == "" {
+				cobra.CheckErr(errors.New("required flag(s) \"public-key\" or \"address\" not set"))
+			}
+
+			// resolve the public key used to sign the transaction at the given address: the address of the
+			// envelope is the one of the transaction signed by the key of its index, so its previous public key is the signer's key
+			if address != "" {
+				transaction, err := tuiutils.GetTransaction(endpoint.String(), address)
+				cobra.CheckErr(err)
+				publicKeyHex = transaction.PreviousPublicKey
+			}
+
+			publicKey, err := hex.DecodeString(publicKeyHex)
+			if err != nil {
+				cobra.CheckErr(errors.New("invalid public key"))
+			}
+			signature, err := encoding.Decode(signatureStr)
+			if err != nil {
+				cobra.CheckErr(errors.New("invalid signature encoding"))
+			}
+
+			valid, err := archethic.Verify(signature, data, publicKey)
+			cobra.CheckErr(err)
+
+			jsonData, err := json.Marshal(VerifiedMessage{
+				Valid:     valid,
+				PublicKey: strings.ToUpper(publicKeyHex),
+				Address:   strings.ToUpper(address),
+			})
+			cobra.CheckErr(err)
+			fmt.Println(string(jsonData))
+			if !valid {
+				os.Exit(1)
+			}
+		},
+	}
+	verifyMessageCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order, only used with an address")
+	verifyMessageCmd.Flags().String("message", "", "Signed message")
+	verifyMessageCmd.Flags().String("file", "", "The file location of the signed data")
+	verifyMessageCmd.Flags().String("signature", "", "Signature")
+	verifyMessageCmd.Flags().String("public-key", "", "Public key of the signer")
+	verifyMessageCmd.Flags().String("address", "", "Address of the transaction signed by the signer's key, as printed by sign-message: its previous public key is the signer's key")
+	verifyMessageCmd.Flags().Var(&encoding, "encoding", "Signature encoding (hex|base64)")
+	verifyMessageCmd.MarkFlagRequired("signature")
+	verifyMessageCmd.MarkFlagsMutuallyExclusive("message", "file")
+	verifyMessageCmd.MarkFlagsMutuallyExclusive("public-key", "address")
+	return verifyMessageCmd
+}
+
+// readMessage returns the message passed with the message flag or the content of the file passed with the file flag
+func readMessage(cmd *cobra.Command) (string, []byte, error) {
+	message, _ := cmd.Flags().GetString("message")
+	file, _ := cmd.Flags().GetString("file")
+	if file != "" {
+		data, err := os.ReadFile(file)
+		return "", data, err
+	}
+	if !cmd.Flags().Changed("message") {
+		return "", nil, errors.New("required flag(s) \"message\" or \"file\" not set")
+	}
+	return message, []byte(message), nil
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/config"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+func GetSendMessageCmd() *cobra.Command {
+	sendMessageCmd := &cobra.Command{
+		Use:   "send-message",
+		Short: "Send a message encrypted for the public key of the recipient",
+		Run: func(cmd *cobra.Command, args []string) {
+			applyProfileEndpoint(cmd)
+			to, _ := cmd.Flags().GetString("to")
+			replyTo, _ := cmd.Flags().GetString("reply-to")
+			amount, _ := cmd.Flags().GetFloat64("amount")
+			if amount <= 0 {
+				cobra.CheckErr(errors.New("the amount must be positive"))
+			}
+			text, err := getMessageText(cmd)
+			cobra.CheckErr(err)
+			err = validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			accessSeed, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			curve, err := ellipticCurve.GetCurve()
+			cobra.CheckErr(err)
+			maxFee, err := getMaxFee(cmd)
+			cobra.CheckErr(err)
+			index, _ := cmd.Flags().GetInt("index")
+			serviceName, _ := cmd.Flags().GetString("service-name")
+
+			toAddress, recipientPublicKey, err := getMessageRecipient(to)
+			cobra.CheckErr(err)
+			toBytes, err := hex.DecodeString(toAddress)
+			cobra.CheckErr(err)
+			if replyTo != "" {
+				_, err = hex.DecodeString(replyTo)
+				cobra.CheckErr(err)
+			}
+			// the first key of our chain is also authorized, to read the sent messages
+			senderKeypairs, err := tuiutils.CandidateKeypairs(endpoint.String(), accessSeed, curve, serviceName, 0)
+			cobra.CheckErr(err)
+
+			secretKey := make([]byte, 32)
+			rand.Read(secretKey)
+			transaction, err := tuiutils.BuildMessageTransaction(text, replyTo, toBytes, ToBigInt(amount, 8), recipientPublicKey, senderKeypairs[0].PublicKey, secretKey)
+			cobra.CheckErr(err)
+			runTransactionAction(cmd, transaction, secretKey, curve, accessSeed, index, serviceName, sendAction(maxFee, false, cmd.Flags().Changed("index")))
+		},
+	}
+	setupSignerFlags(sendMessageCmd)
+	sendMessageCmd.Flags().String("to", "", "Address of the recipient, or @contact")
+	sendMessageCmd.Flags().String("message", "", "The message, or - to read it from the standard input")
+	sendMessageCmd.Flags().String("reply-to", "", "Address of the transaction of the message answered")
+	sendMessageCmd.Flags().Float64("amount", 0.00000001, "Amount of UCO transferred to the recipient, the transfer addressing the message")
+	setupMaxFeeFlag(sendMessageCmd)
+	sendMessageCmd.MarkFlagRequired("to")
+	sendMessageCmd.MarkFlagRequired("message")
+	return sendMessageCmd
+}
+
+func GetReadMessagesCmd() *cobra.Command {
+	readMessagesCmd := &cobra.Command{
+		Use:   "read-messages",
+		Short: "Decrypt the messages received and sent by the chain, and print them as threads",
+		Run: func(cmd *cobra.Command, args []string) {
+			applyProfileEndpoint(cmd)
+			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			accessSeed, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			curve, err := ellipticCurve.GetCurve()
+			cobra.CheckErr(err)
+			serviceName, _ := cmd.Flags().GetString("service-name")
+
+			threads, err := tuiutils.ReadMessages(endpoint.String(), accessSeed, curve, serviceName)
+			cobra.CheckErr(err)
+			err = printMessages(os.Stdout, threads, outputFormat)
+			cobra.CheckErr(err)
+		},
+	}
+	readMessagesCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
+	readMessagesCmd.Flags().String("profile", "", "Profile of the configuration file providing the default endpoint")
+	setupSeedFlags(readMessagesCmd)
+	readMessagesCmd.Flags().String("service-name", "", "Service Name (to read the messages of a keychain's service)")
+	readMessagesCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	return readMessagesCmd
+}
+
+// getMessageText returns the message given by the flag, or read from the standard input
+func getMessageText(cmd *cobra.Command) (string, error) {
+	message, _ := cmd.Flags().GetString("message")
+	if message == "-" {
+		if mnemonic, _ := cmd.Flags().GetBool("mnemonic"); mnemonic {
+			return "", errors.New("the message cannot be read from the standard input when the mnemonic words are prompted")
+		}
+		messageBytes, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		message = string(messageBytes)
+	}
+	if strings.TrimSpace(message) == "" {
+		return "", errors.New("the message is empty")
+	}
+	return message, nil
+}
+
+// getMessageRecipient returns the address of the recipient and its public key: the one of the contact if it has one,
+// otherwise the public key of the last transaction of the recipient's chain
+func getMessageRecipient(to string) (string, []byte, error) {
+	if strings.HasPrefix(to, tuiutils.ContactKeyPrefix) {
+		cfg, err := config.Load()
+		if err != nil {
+			return "", nil, err
+		}
+		name := strings.TrimPrefix(to, tuiutils.ContactKeyPrefix)
+		contact, ok := cfg.Contacts[name]
+		if !ok || contact.Address == "" {
+			return "", nil, fmt.Errorf("unknown contact %s, or without address", name)
+		}
+		if contact.PublicKey != "" {
+			publicKey, err := hex.DecodeString(contact.PublicKey)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid public key of the contact %s: %w", name, err)
+			}
+			return contact.Address, publicKey, nil
+		}
+		to = contact.Address
+	}
+	publicKey, err := tuiutils.GetChainPublicKey(endpoint.String(), to)
+	return to, publicKey, err
+}
+
+func printMessages(w io.Writer, threads []*tuiutils.Message, format OutputFormatCLI) error {
+	if format == JSONFormat {
+		jsonData, err := json.Marshal(threads)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+		return nil
+	}
+	for _, message := range threads {
+		printMessage(w, message, 0)
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// printMessage prints the message and its replies, indented by their depth in the thread
+func printMessage(w io.Writer, message *tuiutils.Message, depth int) {
+	indent := strings.Repeat("  ", depth)
+	counterpart := "from " + message.Address
+	if message.Sent {
+		counterpart = fmt.Sprintf("sent to %s (%s)", message.To, message.Address)
+	}
+	fmt.Fprintf(w, "%s%s %s\n", indent, time.Unix(message.Timestamp, 0).UTC().Format(time.RFC3339), counterpart)
+	for _, line := range strings.Split(strings.TrimRight(message.Text, "\n"), "\n") {
+		fmt.Fprintf(w, "%s  %s\n", indent, line)
+	}
+	for _, reply := range message.Replies {
+		printMessage(w, reply, depth+1)
+	}
+}
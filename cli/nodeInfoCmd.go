@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+func GetNodeInfoCmd() *cobra.Command {
+	nodeInfoCmd := &cobra.Command{
+		Use:   "node-info",
+		Short: "Display the reachability, version, storage nonce public key and oracle rates of the nodes",
+		Run: func(cmd *cobra.Command, args []string) {
+			applyProfileEndpoint(cmd)
+			if outputFormat != TableFormat && outputFormat != JSONFormat {
+				cobra.CheckErr(fmt.Errorf("the %s output is not supported, expecting table or json", outputFormat))
+			}
+			var infos []tuiutils.NodeInfo
+			for _, e := range tuiutils.SplitEndpoints(endpoint.String()) {
+				infos = append(infos, tuiutils.GetNodeInfo(e))
+			}
+			err := printNodeInfos(os.Stdout, infos, outputFormat)
+			cobra.CheckErr(err)
+		},
+	}
+	nodeInfoCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
+	nodeInfoCmd.Flags().String("profile", "", "Profile of the configuration file providing the default endpoint")
+	nodeInfoCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	return nodeInfoCmd
+}
+
+func printNodeInfos(w io.Writer, infos []tuiutils.NodeInfo, format OutputFormatCLI) error {
+	if format == JSONFormat {
+		jsonData, err := json.Marshal(infos)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+		return nil
+	}
+
+	tabWriter := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, info := range infos {
+		if i > 0 {
+			fmt.Fprintln(tabWriter)
+		}
+		fmt.Fprintf(tabWriter, "ENDPOINT\t%s\n", info.Endpoint)
+		fmt.Fprintf(tabWriter, "NETWORK\t%s\n", strings.ToUpper(info.Network))
+		if !info.Reachable {
+			fmt.Fprintf(tabWriter, "STATUS\tunreachable (%s)\n", info.Error)
+			continue
+		}
+		fmt.Fprintf(tabWriter, "STATUS\treachable (%d ms)\n", info.LatencyMs)
+		fmt.Fprintf(tabWriter, "VERSION\t%s (protocol %s, transaction %d)\n", info.Version.Code, info.Version.Protocol, info.Version.Transaction)
+		fmt.Fprintf(tabWriter, "STORAGE NONCE PUBLIC KEY\t%s\n", info.StorageNoncePublicKey)
+		if info.OracleRates.Timestamp != 0 {
+			fmt.Fprintf(tabWriter, "UCO RATES\t$%.4f, %.4f€ (%s)\n", info.OracleRates.Usd, info.OracleRates.Eur, time.Unix(info.OracleRates.Timestamp, 0).UTC().Format(time.RFC3339))
+		}
+		if info.Error != "" {
+			fmt.Fprintf(tabWriter, "ERROR\t%s\n", info.Error)
+		}
+	}
+	return tabWriter.Flush()
+}
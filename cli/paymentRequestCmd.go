@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/config"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+// EncodedPaymentRequest is the payment request with its URI, as printed in JSON
+type EncodedPaymentRequest struct {
+	URI string `json:"uri"`
+	tuiutils.PaymentRequest
+}
+
+func GetPaymentRequestCmd() *cobra.Command {
+	paymentRequestCmd := &cobra.Command{
+		Use:   "payment-request",
+		Short: "Encode a request to pay an address in a URI, displayed as a QR code",
+		Run: func(cmd *cobra.Command, args []string) {
+			request, err := getPaymentRequest(cmd)
+			cobra.CheckErr(err)
+			uri := request.URI()
+			if outputFormat == JSONFormat {
+				// the & of the URI are not escaped
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetEscapeHTML(false)
+				err = encoder.Encode(EncodedPaymentRequest{URI: uri, PaymentRequest: request})
+				cobra.CheckErr(err)
+				return
+			}
+			fmt.Println(uri)
+			if qr, _ := cmd.Flags().GetBool("qr"); qr {
+				invert, _ := cmd.Flags().GetBool("invert")
+				code, err := tuiutils.PaymentQRCode(uri, invert)
+				cobra.CheckErr(err)
+				fmt.Print(code)
+			}
+		},
+	}
+	paymentRequestCmd.Flags().String("address", "", "Address receiving the payment, or @contact")
+	paymentRequestCmd.Flags().String("amount", "", "Amount requested, in UCO or in tokens (default to the amount chosen by the payer)")
+	paymentRequestCmd.Flags().String("token-address", "", "Address of the token requested, instead of UCO")
+	paymentRequestCmd.Flags().Int("token-id", 0, "Id of the token requested")
+	paymentRequestCmd.Flags().String("memo", "", "Memo displayed to the payer, such as an order number")
+	paymentRequestCmd.Flags().Bool("qr", true, "Display the URI as a QR code")
+	paymentRequestCmd.Flags().Bool("invert", false, "Invert the colours of the QR code, for the terminals with a light background")
+	paymentRequestCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	paymentRequestCmd.MarkFlagRequired("address")
+	return paymentRequestCmd
+}
+
+func getPaymentRequest(cmd *cobra.Command) (tuiutils.PaymentRequest, error) {
+	address, _ := cmd.Flags().GetString("address")
+	address, err := resolveContactAddress(address)
+	if err != nil {
+		return tuiutils.PaymentRequest{}, err
+	}
+	tokenAddress, _ := cmd.Flags().GetString("token-address")
+	tokenId, _ := cmd.Flags().GetInt("token-id")
+	memo, _ := cmd.Flags().GetString("memo")
+	request := tuiutils.PaymentRequest{
+		Address:      strings.ToUpper(address),
+		TokenAddress: strings.ToUpper(tokenAddress),
+		TokenId:      tokenId,
+		Memo:         memo,
+	}
+	if amount, _ := cmd.Flags().GetString("amount"); amount != "" {
+		request.Amount, err = tuiutils.ParseAmount(amount)
+		if err != nil {
+			return tuiutils.PaymentRequest{}, err
+		}
+	}
+	return request, request.Validate()
+}
+
+// resolveContactAddress returns the address of the @contact of the configuration file, or the address as is
+func resolveContactAddress(address string) (string, error) {
+	if !strings.HasPrefix(address, tuiutils.ContactKeyPrefix) {
+		return address, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimPrefix(address, tuiutils.ContactKeyPrefix)
+	contact, ok := cfg.Contacts[name]
+	if !ok || contact.Address == "" {
+		return "", fmt.Errorf("unknown contact %s, or without address", name)
+	}
+	return contact.Address, nil
+}
+
+// paymentRequestTransfer converts the payment request of the --uri flag to a UCO or token transfer
+func paymentRequestTransfer(request tuiutils.PaymentRequest) ([]UCOTransfer, []TokenTransfer, error) {
+	if request.Amount == 0 {
+		return nil, nil, fmt.Errorf("the payment request to %s has no amount", request.Address)
+	}
+	amount := FromBigInt(request.Amount, 8)
+	if request.TokenAddress == "" {
+		return []UCOTransfer{{To: request.Address, Amount: amount}}, nil, nil
+	}
+	return nil, []TokenTransfer{{To: request.Address, Amount: amount, TokenAddress: request.TokenAddress, TokenID: request.TokenId}}, nil
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+func GetPendingCmd() *cobra.Command {
+	pendingCmd := &cobra.Command{
+		Use:   "pending",
+		Short: "List the chain indexes reserved by the transactions being sent, or clear the stuck ones",
+		Run: func(cmd *cobra.Command, args []string) {
+			clear, _ := cmd.Flags().GetBool("clear")
+			chain, _ := cmd.Flags().GetString("chain")
+			if clear {
+				removed, err := tuiutils.ClearPending(chain)
+				cobra.CheckErr(err)
+				fmt.Printf("%d pending transaction(s) removed\n", removed)
+				return
+			}
+			// the reservations confirmed by the node are removed before listing the remaining ones
+			pending, err := tuiutils.ReconcilePending()
+			cobra.CheckErr(err)
+			if chain != "" {
+				pending = filterPendingChain(pending, chain)
+			}
+			err = printPendingTransactions(os.Stdout, pending, outputFormat)
+			cobra.CheckErr(err)
+		},
+	}
+	pendingCmd.Flags().Bool("clear", false, "Remove the pending transactions, of the chain given by --chain or all of them")
+	pendingCmd.Flags().String("chain", "", "Genesis address of the chain whose pending transactions are listed or cleared")
+	pendingCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	return pendingCmd
+}
+
+func filterPendingChain(pending []tuiutils.PendingTransaction, chain string) []tuiutils.PendingTransaction {
+	var filtered []tuiutils.PendingTransaction
+	for _, p := range pending {
+		if strings.EqualFold(p.Chain, chain) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+func printPendingTransactions(w io.Writer, pending []tuiutils.PendingTransaction, format OutputFormatCLI) error {
+	if format == JSONFormat {
+		jsonData, err := json.Marshal(pending)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+		return nil
+	}
+
+	tabWriter := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tabWriter, "CHAIN\tINDEX\tADDRESS\tENDPOINT\tAGE\tSTATUS")
+	for _, p := range pending {
+		status := "pending"
+		if p.Stale() {
+			status = "stale"
+		}
+		age := time.Since(p.CreatedAt).Round(time.Second)
+		fmt.Fprintf(tabWriter, "%s\t%d\t%s\t%s\t%s\t%s\n", p.Chain, p.Index, p.Address, p.Endpoint, age, status)
+	}
+	return tabWriter.Flush()
+}
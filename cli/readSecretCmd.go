@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+func GetReadSecretCmd() *cobra.Command {
+	readSecretCmd := &cobra.Command{
+		Use:   "read-secret",
+		Short: "Decrypt the ownership secrets of a transaction",
+		Run: func(cmd *cobra.Command, args []string) {
+			address, _ := cmd.Flags().GetString("address")
+			serviceName, _ := cmd.Flags().GetString("service-name")
+			hexOutput, _ := cmd.Flags().GetBool("hex")
+			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			accessSeedBytes, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			curve, err := ellipticCurve.GetCurve()
+			cobra.CheckErr(err)
+
+			// if no index is provided, all the keys of the chain are tried
+			index := -1
+			if cmd.Flags().Changed("index") {
+				index, _ = cmd.Flags().GetInt("index")
+			}
+
+			keypairs, err := tuiutils.CandidateKeypairs(endpoint.String(), accessSeedBytes, curve, serviceName, index)
+			cobra.CheckErr(err)
+			secrets, err := tuiutils.ReadSecrets(endpoint.String(), address, keypairs)
+			cobra.CheckErr(err)
+			for _, secret := range secrets {
+				if hexOutput {
+					fmt.Println(hex.EncodeToString(secret.Secret))
+				} else {
+					fmt.Println(string(secret.Secret))
+				}
+			}
+		},
+	}
+	readSecretCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
+	readSecretCmd.Flags().String("address", "", "Address of the transaction holding the ownerships")
+	setupSeedFlags(readSecretCmd)
+	readSecretCmd.Flags().Int("index", 0, "Index of the authorized key (default: all the keys of the chain are tried)")
+	readSecretCmd.Flags().String("service-name", "", "Service Name (to use the key of a keychain's service)")
+	readSecretCmd.Flags().Bool("hex", false, "Print the secret hex encoded")
+	readSecretCmd.MarkFlagRequired("address")
+	return readSecretCmd
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/spf13/cobra"
+)
+
+// GetShareSecretCmd decrypts the ownership secret of a transaction with the keys of our chain, and sends a data transaction
+// carrying the same secret, encrypted with a new secret key for the updated set of authorized keys
+func GetShareSecretCmd() *cobra.Command {
+	shareSecretCmd := &cobra.Command{
+		Use:   "share-secret",
+		Short: "Share the ownership secret of a transaction with a new set of authorized keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			applyProfileEndpoint(cmd)
+			address, _ := cmd.Flags().GetString("address")
+			addKeys, _ := cmd.Flags().GetStringSlice("add-key")
+			removeKeys, _ := cmd.Flags().GetStringSlice("remove-key")
+			err := validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			accessSeed, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+			cobra.CheckErr(err)
+			curve, err := ellipticCurve.GetCurve()
+			cobra.CheckErr(err)
+			maxFee, err := getMaxFee(cmd)
+			cobra.CheckErr(err)
+			index, _ := cmd.Flags().GetInt("index")
+			serviceName, _ := cmd.Flags().GetString("service-name")
+
+			// the secret may have been shared with any key of the chain
+			keypairs, err := tuiutils.CandidateKeypairs(endpoint.String(), accessSeed, curve, serviceName, -1)
+			cobra.CheckErr(err)
+			secrets, err := tuiutils.ReadSecrets(endpoint.String(), address, keypairs)
+			cobra.CheckErr(err)
+			ownershipIndex := -1
+			if cmd.Flags().Changed("ownership") {
+				ownershipIndex, _ = cmd.Flags().GetInt("ownership")
+			}
+			secret, err := selectSecret(secrets, ownershipIndex)
+			cobra.CheckErr(err)
+			authorizedKeys, err := shareKeys(secret.AuthorizedKeys, addKeys, removeKeys)
+			cobra.CheckErr(err)
+
+			secretKey := make([]byte, 32)
+			rand.Read(secretKey)
+			cipher, keys, err := tuiutils.EncryptOwnership(secret.Secret, authorizedKeys, secretKey)
+			cobra.CheckErr(err)
+			fmt.Fprintf(os.Stderr, "Secret of the ownership %d shared with %d keys (%d added, %d removed)\n", secret.OwnershipIndex, len(authorizedKeys), len(addKeys), len(removeKeys))
+
+			transaction := archethic.NewTransaction(archethic.DataType)
+			transaction.AddOwnership(cipher, keys)
+			runTransactionAction(cmd, transaction, secretKey, curve, accessSeed, index, serviceName, sendAction(maxFee, false, cmd.Flags().Changed("index")))
+		},
+	}
+	setupSignerFlags(shareSecretCmd)
+	shareSecretCmd.Flags().String("address", "", "Address of the transaction holding the ownership")
+	shareSecretCmd.Flags().Int("ownership", 0, "Index of the ownership in the transaction (required when several secrets can be decrypted)")
+	shareSecretCmd.Flags().StringSlice("add-key", []string{}, "Public key to authorize, in addition to the keys of the ownership")
+	shareSecretCmd.Flags().StringSlice("remove-key", []string{}, "Public key of the ownership to revoke")
+	setupMaxFeeFlag(shareSecretCmd)
+	shareSecretCmd.MarkFlagRequired("address")
+	return shareSecretCmd
+}
+
+// selectSecret returns the decrypted secret of the ownership with the given index,
+// or the only secret decrypted when the index is negative
+func selectSecret(secrets []tuiutils.DecryptedSecret, ownershipIndex int) (tuiutils.DecryptedSecret, error) {
+	if ownershipIndex < 0 {
+		if len(secrets) > 1 {
+			indexes := make([]string, len(secrets))
+			for i, secret := range secrets {
+				indexes[i] = fmt.Sprint(secret.OwnershipIndex)
+			}
+			return tuiutils.DecryptedSecret{}, fmt.Errorf("several ownerships can be decrypted (%s), select one with the ownership flag", strings.Join(indexes, ", "))
+		}
+		return secrets[0], nil
+	}
+	for _, secret := range secrets {
+		if secret.OwnershipIndex == ownershipIndex {
+			return secret, nil
+		}
+	}
+	return tuiutils.DecryptedSecret{}, fmt.Errorf("none of our keys is authorized to decrypt the ownership %d", ownershipIndex)
+}
+
+// shareKeys returns the updated authorized keys, after checking the removed keys were authorized and some keys remain
+func shareKeys(current []string, add []string, remove []string) ([]string, error) {
+	for _, key := range remove {
+		found := false
+		for _, authorizedKey := range current {
+			found = found || strings.EqualFold(key, authorizedKey)
+		}
+		if !found {
+			return nil, fmt.Errorf("the key %s is not authorized by the ownership", key)
+		}
+	}
+	keys := tuiutils.UpdateAuthorizedKeys(current, add, remove)
+	if len(keys) == 0 {
+		return nil, errors.New("the secret must remain shared with at least one key")
+	}
+	return keys, nil
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateFromTransaction describes the transaction in the send-transaction YAML format, without any secret:
+// the access seed and the secrets of the ownerships are left out, only the authorized public keys are kept.
+// The content is saved before its encoding, given by the caller, so the template can be edited.
+func TemplateFromTransaction(transaction *archethic.TransactionBuilder, endpoint string, curve archethic.Curve, serviceName string, content []byte, contentEncoding tuiutils.ContentEncoding) SendTransactionData {
+	curveCLI := CurveCLI(curve)
+	transactionTypeCLI := TransactionTypeCLI(transaction.TxType)
+	data := SendTransactionData{
+		Endpoint:        endpoint,
+		EllipticCurve:   curveCLI.String(),
+		TransactionType: transactionTypeCLI.String(),
+		Content:         string(content),
+		SmartContract:   string(transaction.Data.Code),
+		ServiceName:     serviceName,
+	}
+	if contentEncoding != tuiutils.RawContent {
+		data.ContentEncoding = string(contentEncoding)
+	}
+	// a binary content can't be written in a YAML or JSON string, and a content starting with file: would be read as a reference
+	if tuiutils.IsBinaryContent(content) || strings.HasPrefix(data.Content, "file:") {
+		data.Content = base64.StdEncoding.EncodeToString(content)
+		data.ContentBase64 = true
+	}
+	// the curve is derived from the service in the keychain
+	if serviceName != "" {
+		data.EllipticCurve = ""
+	}
+
+	for _, transfer := range transaction.Data.Ledger.Uco.Transfers {
+		data.UcoTransfers = append(data.UcoTransfers, UCOTransfer{
+			To:     hex.EncodeToString(transfer.To),
+			Amount: FromBigInt(transfer.Amount, 8),
+		})
+	}
+	for _, transfer := range transaction.Data.Ledger.Token.Transfers {
+		data.TokenTransfers = append(data.TokenTransfers, TokenTransfer{
+			To:           hex.EncodeToString(transfer.To),
+			Amount:       FromBigInt(transfer.Amount, 8),
+			TokenAddress: hex.EncodeToString(transfer.TokenAddress),
+			TokenID:      transfer.TokenId,
+		})
+	}
+	for _, recipient := range transaction.Data.Recipients {
+		data.Recipients = append(data.Recipients, hex.EncodeToString(recipient))
+	}
+	for _, ownership := range transaction.Data.Ownerships {
+		authorizedKeys := make([]string, len(ownership.AuthorizedKeys))
+		for i, authorizedKey := range ownership.AuthorizedKeys {
+			authorizedKeys[i] = hex.EncodeToString(authorizedKey.PublicKey)
+		}
+		data.Ownerships = append(data.Ownerships, Ownership{AuthorizedKeys: authorizedKeys})
+	}
+	return data
+}
+
+// WriteTransactionTemplate saves the transaction template as a YAML file, or as a JSON file when the path ends with .json
+func WriteTransactionTemplate(path string, data SendTransactionData) error {
+	var dataBytes []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dataBytes, err = json.MarshalIndent(data, "", "  ")
+	} else {
+		dataBytes, err = yaml.Marshal(data)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, dataBytes, 0600)
+}
+
+// ReadTransactionTemplate loads a transaction template from a YAML or JSON file.
+// Unknown fields are rejected, the ${ENV_VAR} and file: references are resolved and a base64 content is decoded.
+func ReadTransactionTemplate(path string) (SendTransactionData, error) {
+	var data SendTransactionData
+	dataBytes, err := os.ReadFile(path)
+	if err != nil {
+		return data, err
+	}
+	if isJSON(path, dataBytes) {
+		err = decodeJSONStrict(dataBytes, &data)
+	} else {
+		err = decodeYAMLStrict(dataBytes, &data)
+	}
+	if err != nil {
+		return data, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	var content []byte
+	if data.ContentBase64 {
+		content, err = base64.StdEncoding.DecodeString(data.Content)
+		if err != nil {
+			return data, fmt.Errorf("invalid configuration file %s: content: %w", path, err)
+		}
+		// the decoded content is not a reference
+		data.Content = ""
+	}
+	err = resolveReferences(&data, filepath.Dir(path))
+	if err != nil {
+		return data, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if data.ContentBase64 {
+		data.Content = string(content)
+		data.ContentBase64 = false
+	}
+	return data, nil
+}
+
+func isJSON(path string, data []byte) bool {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return true
+	}
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+func decodeYAMLStrict(data []byte, v interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(v)
+	// an empty file is an empty configuration
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+func decodeJSONStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err != nil {
+		var syntaxError *json.SyntaxError
+		var typeError *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxError):
+			return fmt.Errorf("line %d: %w", lineAt(data, syntaxError.Offset), err)
+		case errors.As(err, &typeError):
+			return fmt.Errorf("line %d: %w", lineAt(data, typeError.Offset), err)
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			// the decoder does not report the position of unknown fields, look for the first occurrence of the field
+			field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+			if offset := bytes.Index(data, []byte(field)); offset >= 0 {
+				return fmt.Errorf("line %d: %w", lineAt(data, int64(offset)), err)
+			}
+		}
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("line %d: unexpected data after the configuration object", lineAt(data, decoder.InputOffset()))
+	}
+	return nil
+}
+
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolveReferences replaces the ${ENV_VAR} and file: references of the endpoint and the secrets, and the file: references
+// of the content and the code. The ${...} of the content and the code are kept as is: they can be part of a contract or a JSON document.
+func resolveReferences(data *SendTransactionData, baseDir string) error {
+	type reference struct {
+		name  string
+		value *string
+		// the ${ENV_VAR} references are only expanded in the endpoint and the secrets, and the trailing new line
+		// of the files containing them is not part of the value
+		secret bool
+	}
+	references := []reference{
+		{"endpoint", &data.Endpoint, true},
+		{"access_seed", &data.AccessSeed, true},
+		{"content", &data.Content, false},
+		{"smart_contract", &data.SmartContract, false},
+	}
+	for i := range data.Ownerships {
+		references = append(references, reference{fmt.Sprintf("ownerships[%d].secret", i), &data.Ownerships[i].Secret, true})
+	}
+	for _, r := range references {
+		value, err := resolveReference(*r.value, baseDir, r.secret)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.name, err)
+		}
+		*r.value = value
+	}
+	return nil
+}
+
+// resolveReference expands the ${ENV_VAR} references of a secret value, then, if the value is a single line starting with file:,
+// reads the referenced file (relative paths are relative to the configuration file)
+func resolveReference(value string, baseDir string, secret bool) (string, error) {
+	if secret {
+		var missing []string
+		value = envReference.ReplaceAllStringFunc(value, func(match string) string {
+			name := envReference.FindStringSubmatch(match)[1]
+			envValue, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return envValue
+		})
+		if len(missing) > 0 {
+			return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+		}
+	}
+
+	path, isFile := strings.CutPrefix(value, "file:")
+	// a content or a code of several lines starting with file: is not a reference
+	if !isFile || strings.ContainsAny(path, "\r\n") {
+		return value, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if secret {
+		return strings.TrimRight(string(fileBytes), "\r\n"), nil
+	}
+	return string(fileBytes), nil
+}
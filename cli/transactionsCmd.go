@@ -3,29 +3,28 @@ package cli
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/archethic-foundation/archethic-cli/config"
+	"github.com/archethic-foundation/archethic-cli/logging"
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 func extractTransactionFromInputFile(config string) (ConfiguredTransaction, SendTransactionData, error) {
-	configBytes, err := os.ReadFile(config)
+	data, err := ReadTransactionTemplate(config)
 	if err != nil {
 		return ConfiguredTransaction{}, SendTransactionData{}, err
 	}
-	var data SendTransactionData
-	err = yaml.Unmarshal(configBytes, &data)
-	if err != nil {
-		return ConfiguredTransaction{}, SendTransactionData{}, err
-	}
-	seedByte, err := archethic.MaybeConvertToHex(data.AccessSeed)
+	seedByte, err := tuiutils.DecodeSecret(data.AccessSeed)
 	if err != nil {
 		return ConfiguredTransaction{}, data, err
 	}
@@ -45,7 +44,7 @@ func extractTransactionFromInputFile(config string) (ConfiguredTransaction, Send
 
 func extractTransactionFromInputFlags(cmd *cobra.Command) (ConfiguredTransaction, error) {
 	index, _ := cmd.Flags().GetInt("index")
-	serviceName, _ := cmd.Flags().GetString("serviceName")
+	serviceName, _ := cmd.Flags().GetString("service-name")
 	recipients, _ := cmd.Flags().GetStringSlice("recipients")
 
 	// extract uco transfers
@@ -87,6 +86,23 @@ func extractTransactionFromInputFlags(cmd *cobra.Command) (ConfiguredTransaction
 		})
 	}
 
+	// the transfer of the payment request is added to the ones of the flags
+	if uri, _ := cmd.Flags().GetString("uri"); uri != "" {
+		request, err := tuiutils.ParsePaymentRequest(uri)
+		if err != nil {
+			return ConfiguredTransaction{}, err
+		}
+		uriUcoTransfers, uriTokenTransfers, err := paymentRequestTransfer(request)
+		if err != nil {
+			return ConfiguredTransaction{}, err
+		}
+		ucoTransfers = append(ucoTransfers, uriUcoTransfers...)
+		tokenTransfers = append(tokenTransfers, uriTokenTransfers...)
+		if request.Memo != "" {
+			fmt.Fprintf(os.Stderr, "Memo of the payment request: %s\n", request.Memo)
+		}
+	}
+
 	// extract ownerships
 	ownershipsStr, _ := cmd.Flags().GetStringToString("ownerships")
 	var ownerships []Ownership
@@ -98,15 +114,27 @@ func extractTransactionFromInputFlags(cmd *cobra.Command) (ConfiguredTransaction
 		})
 	}
 
-	// extract content
+	// extract content, from a file, the standard input or the flag's value
 	content, _ := cmd.Flags().GetString("content")
 	contentBytes := []byte{}
 	var err error
-	if content != "" {
+	switch {
+	case content == "-":
+		if mnemonic, _ := cmd.Flags().GetBool("mnemonic"); mnemonic {
+			return ConfiguredTransaction{}, errors.New("the content cannot be read from the standard input when the mnemonic words are prompted")
+		}
+		contentBytes, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return ConfiguredTransaction{}, err
+		}
+	case content != "":
 		contentBytes, err = os.ReadFile(content)
 		if err != nil {
 			return ConfiguredTransaction{}, err
 		}
+	case cmd.Flags().Changed("content-string"):
+		contentString, _ := cmd.Flags().GetString("content-string")
+		contentBytes = []byte(contentString)
 	}
 
 	// extract smart contract
@@ -188,6 +216,9 @@ func configureTransaction(configuredTransaction ConfiguredTransaction, txType ar
 
 	// set uco transfers
 	for _, ucoTransfer := range configuredTransaction.ucoTransfers {
+		if ucoTransfer.Amount < 0 {
+			return nil, fmt.Errorf("the amount of the UCO transfer to %s cannot be negative", ucoTransfer.To)
+		}
 		toBytes, err := hex.DecodeString(ucoTransfer.To)
 		if err != nil {
 			return nil, err
@@ -197,6 +228,9 @@ func configureTransaction(configuredTransaction ConfiguredTransaction, txType ar
 
 	// set token transfers
 	for _, tokenTransfer := range configuredTransaction.tokenTransfers {
+		if tokenTransfer.Amount < 0 {
+			return nil, fmt.Errorf("the amount of the token transfer to %s cannot be negative", tokenTransfer.To)
+		}
 		toBytes, err := hex.DecodeString(tokenTransfer.To)
 		if err != nil {
 			return nil, err
@@ -220,6 +254,8 @@ func configureTransaction(configuredTransaction ConfiguredTransaction, txType ar
 
 	// set ownerships
 	for _, ownership := range configuredTransaction.ownerships {
+		// the secrets of the flags, of the configuration file and of the templates are redacted from the logs
+		logging.AddSecret(ownership.Secret)
 		cipher, err := archethic.AesEncrypt([]byte(ownership.Secret), secretKey)
 		if err != nil {
 			return nil, err
@@ -262,7 +298,54 @@ func mapOwnership(ownerships map[string]string) map[string][]string {
 	return result
 }
 
-func extractAndPrepareTransaction(cmd *cobra.Command, args []string, action func(*archethic.TransactionBuilder, []byte, archethic.Curve, bool, string, int, string, string, []byte) (interface{}, error)) {
+// resolveAuthorizedKeys replaces the service:NAME and @contact references of the ownerships by their public key,
+// and rejects the keys authorized twice in an ownership
+func resolveAuthorizedKeys(ownerships []Ownership, accessSeed []byte) ([]Ownership, error) {
+	if len(ownerships) == 0 {
+		return ownerships, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	resolver := tuiutils.KeyResolver{Endpoint: endpoint.String(), AccessSeed: accessSeed, Contacts: cfg.Contacts}
+	resolved := make([]Ownership, len(ownerships))
+	for i, ownership := range ownerships {
+		seen := map[string]bool{}
+		keys := make([]string, len(ownership.AuthorizedKeys))
+		for j, reference := range ownership.AuthorizedKeys {
+			key, err := resolver.Resolve(reference)
+			if err != nil {
+				return nil, fmt.Errorf("invalid authorized key of the ownership %d: %w", i, err)
+			}
+			if seen[key] {
+				return nil, fmt.Errorf("the key %s is authorized twice in the ownership %d", reference, i)
+			}
+			seen[key] = true
+			keys[j] = key
+		}
+		resolved[i] = Ownership{Secret: ownership.Secret, AuthorizedKeys: keys}
+	}
+	return resolved, nil
+}
+
+// transactionAction is run on the built transaction with the parameters of its chain
+type transactionAction func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index int, serviceName string, storageNouncePublicKey string, seed []byte) (interface{}, error)
+
+func extractAndPrepareTransaction(cmd *cobra.Command, args []string, action transactionAction) {
+	configuredTransaction, transaction, secretKey := extractTransaction(cmd)
+
+	err := checkAccessSeed(configuredTransaction.accessSeed)
+	cobra.CheckErr(err)
+
+	curve, err := ellipticCurve.GetCurve()
+	cobra.CheckErr(err)
+
+	runTransactionAction(cmd, transaction, secretKey, curve, configuredTransaction.accessSeed, configuredTransaction.index, configuredTransaction.serviceName, action)
+}
+
+// extractTransaction builds the transaction described by the configuration file and the flags, the ownerships' secrets being encrypted with the returned secret key
+func extractTransaction(cmd *cobra.Command) (ConfiguredTransaction, *archethic.TransactionBuilder, []byte) {
 	secretKey := make([]byte, 32)
 	rand.Read(secretKey)
 
@@ -272,27 +355,41 @@ func extractAndPrepareTransaction(cmd *cobra.Command, args []string, action func
 	var err error
 	if config != "" {
 		fileConfig, sendTransactionData, err = extractTransactionFromInputFile(config)
-		if sendTransactionData.Endpoint != "" {
-			endpoint.Set(sendTransactionData.Endpoint)
+		cobra.CheckErr(err)
+		// the flags override the values of the file
+		if sendTransactionData.Endpoint != "" && !cmd.Flags().Changed("endpoint") {
+			err = endpoint.Set(sendTransactionData.Endpoint)
+			cobra.CheckErr(fieldError("endpoint", err))
 		}
-		if sendTransactionData.EllipticCurve != "" {
-			ellipticCurve.Set(sendTransactionData.EllipticCurve)
+		if sendTransactionData.EllipticCurve != "" && !cmd.Flags().Changed("elliptic-curve") {
+			err = ellipticCurve.Set(sendTransactionData.EllipticCurve)
+			cobra.CheckErr(fieldError("elliptic_curve", err))
 		}
-		if sendTransactionData.TransactionType != "" {
-			transactionType.Set(sendTransactionData.TransactionType)
+		if sendTransactionData.TransactionType != "" && !cmd.Flags().Changed("transaction-type") {
+			err = transactionType.Set(sendTransactionData.TransactionType)
+			cobra.CheckErr(fieldError("transaction_type", err))
 		}
+		if sendTransactionData.ContentEncoding != "" && !cmd.Flags().Changed("content-encoding") {
+			err = contentEncoding.Set(sendTransactionData.ContentEncoding)
+			cobra.CheckErr(fieldError("content_encoding", err))
+		}
+	}
+	// the endpoint of the profile is used when none is given by the flags or the file
+	profile, err := loadProfile(cmd)
+	cobra.CheckErr(err)
+	if profile.Endpoint != "" && !cmd.Flags().Changed("endpoint") && sendTransactionData.Endpoint == "" {
+		err = endpoint.Set(profile.Endpoint)
 		cobra.CheckErr(err)
 	}
+
 	flagConfig, err = extractTransactionFromInputFlags(cmd)
 	cobra.CheckErr(err)
 
 	// merging the config based on file with the one based on flags
 	configuredTransaction = combineTransactions(fileConfig, flagConfig)
-
-	err = checkAccessSeed(configuredTransaction.accessSeed)
+	configuredTransaction.ownerships, err = resolveAuthorizedKeys(configuredTransaction.ownerships, configuredTransaction.accessSeed)
 	cobra.CheckErr(err)
-
-	curve, err := ellipticCurve.GetCurve()
+	configuredTransaction.content, err = tuiutils.EncodeContent(configuredTransaction.content, tuiutils.ContentEncoding(contentEncoding))
 	cobra.CheckErr(err)
 
 	txType, err := transactionType.GetTransactionType()
@@ -300,60 +397,190 @@ func extractAndPrepareTransaction(cmd *cobra.Command, args []string, action func
 
 	transaction, err := configureTransaction(configuredTransaction, txType, secretKey)
 	cobra.CheckErr(err)
+	return configuredTransaction, transaction, secretKey
+}
 
-	serviceMode := configuredTransaction.serviceName != ""
-
-	client := archethic.NewAPIClient(endpoint.String())
+// runTransactionAction resolves the index of the transaction and the storage nonce public key, then runs the action and prints its result
+func runTransactionAction(cmd *cobra.Command, transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, accessSeed []byte, index int, serviceName string, action transactionAction) {
+	var err error
+	serviceMode := serviceName != ""
 
 	// if no index is provided and not in serviceMode, get the last transaction index
 	if !cmd.Flags().Changed("index") && !serviceMode {
-		address, err := archethic.DeriveAddress([]byte(configuredTransaction.accessSeed), 0, curve, archethic.SHA256)
+		index, err = tuiutils.GetLastTransactionIndex(endpoint.String(), curve, accessSeed)
 		cobra.CheckErr(err)
-		addressHex := hex.EncodeToString(address)
-		configuredTransaction.index = client.GetLastTransactionIndex(addressHex)
 	}
 
-	storageNouncePublicKey, err := client.GetStorageNoncePublicKey()
+	storageNouncePublicKey, err := tuiutils.GetStorageNoncePublicKey(endpoint.String())
 	cobra.CheckErr(err)
 
-	result, err := action(transaction, secretKey, curve, serviceMode, endpoint.String(), configuredTransaction.index, configuredTransaction.serviceName, storageNouncePublicKey, configuredTransaction.accessSeed)
+	result, err := action(transaction, secretKey, curve, serviceMode, endpoint.String(), index, serviceName, storageNouncePublicKey, accessSeed)
 	cobra.CheckErr(err)
 	fmt.Println(result)
 }
 
+// sendAction sends the transaction, after checking it against the rules of the chain (unless skipped)
+// and checking its fee doesn't exceed the maximum fee (when greater than 0). An explicit index is used as is,
+// otherwise the index following the node's one and the pending transactions of the chain is used.
+func sendAction(maxFee float64, skipValidation bool, explicitIndex bool) transactionAction {
+	return func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index int, serviceName string, storageNouncePublicKey string, seed []byte) (interface{}, error) {
+		if !skipValidation {
+			err := checkTransaction(transaction)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// the fee estimated to check the maximum fee is recorded in the journal, without estimating it again
+		var estimatedFee *archethic.Fee
+		if maxFee > 0 {
+			fee, err := tuiutils.GetTransactionFee(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, seed)
+			if err != nil {
+				return nil, err
+			}
+			err = CheckMaxFee(fee, maxFee)
+			if err != nil {
+				return nil, err
+			}
+			estimatedFee = &fee
+		}
+		if !explicitIndex {
+			index = tuiutils.NextIndex
+		}
+		return tuiutils.SendTransaction(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, seed, estimatedFee)
+	}
+}
+
 func GetSendTransactionCmd() *cobra.Command {
 	sendTransactionCmd := &cobra.Command{
 		Use:   "send-transaction",
 		Short: "Send transaction",
 		Run: func(cmd *cobra.Command, args []string) {
-			extractAndPrepareTransaction(cmd, args, func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index int, serviceName string, storageNouncePublicKey string, seed []byte) (interface{}, error) {
-				return tuiutils.SendTransaction(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, seed)
-			})
+			maxFee, err := getMaxFee(cmd)
+			cobra.CheckErr(err)
+			skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+			extractAndPrepareTransaction(cmd, args, sendAction(maxFee, skipValidation, cmd.Flags().Changed("index")))
 		},
 	}
 
 	setupTransactionFlags(sendTransactionCmd)
+	setupMaxFeeFlag(sendTransactionCmd)
+	sendTransactionCmd.Flags().Bool("skip-validation", false, "Send the transaction without checking it against the rules of the chain")
 	return sendTransactionCmd
 }
 
+func GetValidateTransactionCmd() *cobra.Command {
+	validateTransactionCmd := &cobra.Command{
+		Use:   "validate-transaction",
+		Short: "Check a transaction against the rules of the chain without sending it",
+		Run: func(cmd *cobra.Command, args []string) {
+			_, transaction, _ := extractTransaction(cmd)
+			findings := tuiutils.ValidateTransaction(transaction)
+			err := printValidationFindings(os.Stdout, findings, outputFormat)
+			cobra.CheckErr(err)
+			if tuiutils.HasValidationErrors(findings) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	setupTransactionFlags(validateTransactionCmd)
+	validateTransactionCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	return validateTransactionCmd
+}
+
+// checkTransaction prints the findings of the validation of the transaction on the standard error,
+// and returns an error when the chain would reject the transaction
+func checkTransaction(transaction *archethic.TransactionBuilder) error {
+	findings := tuiutils.ValidateTransaction(transaction)
+	for _, finding := range findings {
+		fmt.Fprintln(os.Stderr, finding)
+	}
+	if tuiutils.HasValidationErrors(findings) {
+		return errors.New("transaction not sent: it breaks the rules of the chain (use --skip-validation to send it anyway)")
+	}
+	return nil
+}
+
+func printValidationFindings(w io.Writer, findings []tuiutils.ValidationFinding, format OutputFormatCLI) error {
+	if format == JSONFormat {
+		if findings == nil {
+			findings = []tuiutils.ValidationFinding{}
+		}
+		jsonData, err := json.Marshal(findings)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+		return nil
+	}
+
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "The transaction is valid")
+		return nil
+	}
+	tabWriter := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tabWriter, "SEVERITY\tFIELD\tMESSAGE")
+	for _, finding := range findings {
+		message := finding.Message
+		if finding.Line != 0 {
+			message = fmt.Sprintf("line %d: %s", finding.Line, message)
+		}
+		fmt.Fprintf(tabWriter, "%s\t%s\t%s\n", finding.Severity, finding.Field, message)
+	}
+	return tabWriter.Flush()
+}
+
 func GetGetTransactionFeeCmd() *cobra.Command {
 	getTransactionFeeCmd := &cobra.Command{
 		Use:   "get-transaction-fee",
 		Short: "Get transaction fee",
 		Run: func(cmd *cobra.Command, args []string) {
+			humanReadable, _ := cmd.Flags().GetBool("human-readable")
 			extractAndPrepareTransaction(cmd, args, func(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, index int, serviceName string, storageNouncePublicKey string, seed []byte) (interface{}, error) {
+				if humanReadable {
+					fee, err := tuiutils.GetTransactionFee(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, seed)
+					if err != nil {
+						return nil, err
+					}
+					return tuiutils.FormatFee(fee), nil
+				}
 				return tuiutils.GetTransactionFeeJson(transaction, secretKey, curve, serviceMode, endpoint, index, serviceName, storageNouncePublicKey, seed)
 			})
 		},
 	}
 
 	setupTransactionFlags(getTransactionFeeCmd)
+	getTransactionFeeCmd.Flags().Bool("human-readable", false, "Print the fee in UCO with its fiat equivalent instead of the JSON returned by the node")
 	return getTransactionFeeCmd
 }
 
 func setupTransactionFlags(cmd *cobra.Command) {
+	setupSignerFlags(cmd)
 	cmd.Flags().String("config", "", "The file location of the YAML configuration file")
-	cmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url])")
+	cmd.Flags().Var(&transactionType, "transaction-type", "Transaction Type (keychain_access|keychain|transfer|hosting|token|data|contract|code_proposal|code_approval)")
+	cmd.Flags().StringToString("uco-transfer", map[string]string{}, "UCO Transfers (format: to=amount)")
+	cmd.Flags().StringToString("token-transfer", map[string]string{}, "Token Transfers (format: to=amount,token_address,token_id)")
+	cmd.Flags().String("uri", "", "Payment request URI (archethic:address?amount=...), adding its UCO or token transfer")
+	cmd.Flags().StringSlice("recipients", []string{}, "Recipients")
+	cmd.Flags().StringToString("ownerships", map[string]string{}, "Ownerships (format: secret=authorization_key, the key being a public key, service:NAME or @contact)")
+	cmd.Flags().String("content", "", "The file location of the content, or - to read it from the standard input")
+	cmd.Flags().String("content-string", "", "The content of the transaction")
+	cmd.MarkFlagsMutuallyExclusive("content", "content-string")
+	cmd.Flags().Var(&contentEncoding, "content-encoding", "Encoding applied to the content before sending it (raw|gzip|base64|gzip+base64)")
+	cmd.Flags().String("smart-contract", "", "The file location containing the smart Contract")
+}
+
+// setupSignerFlags adds the flags selecting the chain signing the transaction and the node it is sent to
+func setupSignerFlags(cmd *cobra.Command) {
+	cmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
+	setupSeedFlags(cmd)
+	cmd.Flags().Int("index", 0, "Index")
+	cmd.Flags().String("service-name", "", "Service Name (required if creating a transaction for a service)")
+	cmd.Flags().String("profile", "", "Profile of the configuration file providing the default values (default to the default_profile of the configuration file)")
+}
+
+// setupSeedFlags adds the flags giving the seed of the chain (access seed, ssh key or mnemonic words) and its curve
+func setupSeedFlags(cmd *cobra.Command) {
 	cmd.Flags().String("access-seed", "", "Access Seed")
 	cmd.Flags().Bool("ssh", false, "Enable SSH key mode")
 	cmd.Flags().String("ssh-path", GetFirstSshKeyDefaultPath(), "Path to ssh key")
@@ -363,16 +590,64 @@ func setupTransactionFlags(cmd *cobra.Command) {
 	cmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh")
 	cmd.MarkFlagsMutuallyExclusive("mnemonic", "ssh-path")
 	cmd.MarkFlagsMutuallyExclusive("mnemonic", "access-seed")
-	cmd.Flags().Int("index", 0, "Index")
 	cmd.Flags().Var(&ellipticCurve, "elliptic-curve", "Elliptic Curve (ED25519|P256|SECP256K1)")
-	cmd.Flags().Var(&transactionType, "transaction-type", "Transaction Type (keychain_access|keychain|transfer|hosting|token|data|contract|code_proposal|code_approval)")
-	cmd.Flags().StringToString("uco-transfer", map[string]string{}, "UCO Transfers (format: to=amount)")
-	cmd.Flags().StringToString("token-transfer", map[string]string{}, "Token Transfers (format: to=amount,token_address,token_id)")
-	cmd.Flags().StringSlice("recipients", []string{}, "Recipients")
-	cmd.Flags().StringToString("ownerships", map[string]string{}, "Ownerships (format: secret=authorization_key)")
-	cmd.Flags().String("content", "", "The file location of the content")
-	cmd.Flags().String("smart-contract", "", "The file location containing the smart Contract")
-	cmd.Flags().String("serviceName", "", "Service Name (required if creating a transaction for a service)")
+}
+
+// loadProfile returns the profile selected by the profile flag, or the default one
+func loadProfile(cmd *cobra.Command) (config.Profile, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.Profile{}, err
+	}
+	name, _ := cmd.Flags().GetString("profile")
+	return cfg.Profile(name)
+}
+
+// applyProfileEndpoint uses the endpoint of the profile when the endpoint flag is not set
+func applyProfileEndpoint(cmd *cobra.Command) {
+	profile, err := loadProfile(cmd)
+	cobra.CheckErr(err)
+	if profile.Endpoint != "" && !cmd.Flags().Changed("endpoint") {
+		err = endpoint.Set(profile.Endpoint)
+		cobra.CheckErr(err)
+	}
+}
+
+// setupMaxFeeFlag adds the max-fee flag of the commands sending a transaction, read by getMaxFee
+func setupMaxFeeFlag(cmd *cobra.Command) {
+	cmd.Flags().Float64("max-fee", 0, "Maximum fee in UCO: the transaction is not sent if its estimated fee is greater (default to the max_fee of the profile, 0 for no limit)")
+}
+
+// getMaxFee returns the maximum fee given by the max-fee flag, or by the profile
+func getMaxFee(cmd *cobra.Command) (float64, error) {
+	if cmd.Flags().Changed("max-fee") {
+		maxFee, _ := cmd.Flags().GetFloat64("max-fee")
+		if maxFee < 0 {
+			return 0, errors.New("the maximum fee must be positive")
+		}
+		return maxFee, nil
+	}
+	profile, err := loadProfile(cmd)
+	if err != nil {
+		return 0, err
+	}
+	return profile.MaxFee, nil
+}
+
+// CheckMaxFee returns an error when the estimated fee exceeds the maximum fee (in UCO)
+func CheckMaxFee(fee archethic.Fee, maxFee float64) error {
+	if uint64(fee.Fee) > ToBigInt(maxFee, 8) {
+		return fmt.Errorf("transaction not sent: the estimated fee %s exceeds the maximum fee of %s UCO", tuiutils.FormatFee(fee), tuiutils.FormatAmount(ToBigInt(maxFee, 8)))
+	}
+	return nil
+}
+
+// fieldError prefixes the error with the name of the configuration file's field
+func fieldError(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid %s in the configuration file: %w", field, err)
 }
 
 func checkAccessSeed(accessSeed []byte) error {
@@ -1,12 +1,20 @@
 package cli
 
 import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
-	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
 	"github.com/spf13/pflag"
 )
@@ -16,38 +24,43 @@ var (
 	ellipticCurve   = ED25519
 	endpoint        = mainnet
 	transactionType = TransferType
+	outputFormat    = TableFormat
+	encoding        = HexEncoding
+	contentEncoding = ContentEncodingCLI(tuiutils.RawContent)
 )
 
 type SendTransactionData struct {
-	Endpoint        string          `yaml:"endpoint"`
-	AccessSeed      string          `yaml:"access_seed"`
-	Index           int             `yaml:"index"`
-	EllipticCurve   string          `yaml:"elliptic_curve"`
-	TransactionType string          `yaml:"transaction_type"`
-	UcoTransfers    []UCOTransfer   `yaml:"uco_transfers,omitempty"`
-	TokenTransfers  []TokenTransfer `yaml:"token_transfers,omitempty"`
-	Recipients      []string        `yaml:"recipients,omitempty"`
-	Ownerships      []Ownership     `yaml:"ownerships,omitempty"`
-	Content         string          `yaml:"content,omitempty"`
-	SmartContract   string          `yaml:"smart_contract,omitempty"`
-	ServiceName     string          `yaml:"serviceName,omitempty"`
+	Endpoint        string          `yaml:"endpoint,omitempty" json:"endpoint,omitempty" description:"Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order"`
+	AccessSeed      string          `yaml:"access_seed,omitempty" json:"access_seed,omitempty" description:"Access seed, preferably given as a ${ENV_VAR} or file: reference"`
+	Index           int             `yaml:"index,omitempty" json:"index,omitempty" description:"Index of the transaction in the chain"`
+	EllipticCurve   string          `yaml:"elliptic_curve,omitempty" json:"elliptic_curve,omitempty" description:"Elliptic curve of the keys"`
+	TransactionType string          `yaml:"transaction_type,omitempty" json:"transaction_type,omitempty" description:"Type of the transaction"`
+	UcoTransfers    []UCOTransfer   `yaml:"uco_transfers,omitempty" json:"uco_transfers,omitempty" description:"UCO transfers"`
+	TokenTransfers  []TokenTransfer `yaml:"token_transfers,omitempty" json:"token_transfers,omitempty" description:"Token transfers"`
+	Recipients      []string        `yaml:"recipients,omitempty" json:"recipients,omitempty" description:"Addresses of the smart contracts to call"`
+	Ownerships      []Ownership     `yaml:"ownerships,omitempty" json:"ownerships,omitempty" description:"Secrets and the public keys authorized to decrypt them"`
+	Content         string          `yaml:"content,omitempty" json:"content,omitempty" description:"Content of the transaction, or a file: reference"`
+	ContentEncoding string          `yaml:"content_encoding,omitempty" json:"content_encoding,omitempty" description:"Encoding applied to the content before sending it"`
+	ContentBase64   bool            `yaml:"content_base64,omitempty" json:"content_base64,omitempty" description:"The content is written in base64, to hold a binary content"`
+	SmartContract   string          `yaml:"smart_contract,omitempty" json:"smart_contract,omitempty" description:"Code of the smart contract, or a file: reference"`
+	ServiceName     string          `yaml:"serviceName,omitempty" json:"serviceName,omitempty" description:"Name of the keychain service sending the transaction"`
 }
 
 type UCOTransfer struct {
-	To     string  `yaml:"to"`
-	Amount float64 `yaml:"amount"`
+	To     string  `yaml:"to" json:"to" description:"Address of the recipient"`
+	Amount float64 `yaml:"amount" json:"amount" description:"Amount of UCO"`
 }
 
 type TokenTransfer struct {
-	To           string  `yaml:"to"`
-	Amount       float64 `yaml:"amount"`
-	TokenAddress string  `yaml:"token_address"`
-	TokenID      int     `yaml:"token_id"`
+	To           string  `yaml:"to" json:"to" description:"Address of the recipient"`
+	Amount       float64 `yaml:"amount" json:"amount" description:"Amount of tokens"`
+	TokenAddress string  `yaml:"token_address" json:"token_address" description:"Address of the token"`
+	TokenID      int     `yaml:"token_id" json:"token_id" description:"Id of the token"`
 }
 
 type Ownership struct {
-	Secret         string   `yaml:"secret"`
-	AuthorizedKeys []string `yaml:"authorized_keys"`
+	Secret         string   `yaml:"secret,omitempty" json:"secret,omitempty" description:"Secret, preferably given as a ${ENV_VAR} or file: reference"`
+	AuthorizedKeys []string `yaml:"authorized_keys" json:"authorized_keys" description:"Public keys authorized to decrypt the secret, or references to the key of a keychain's service (service:NAME) or of a contact (@NAME)"`
 }
 
 type ConfiguredTransaction struct {
@@ -70,38 +83,39 @@ const (
 	mainnet EndpointCLI = "mainnet"
 )
 
+// String resolves the keywords of the endpoint list and returns the
+// comma-separated urls, in the order they will be tried.
 func (e *EndpointCLI) String() string {
-	switch *e {
-	case local:
-		return "http://localhost:4000"
-	case testnet:
-		return "https://testnet.archethic.net"
-	case mainnet:
-		return "https://mainnet.archethic.net"
-	default:
-		_, err := url.Parse(string(*e))
-		if err != nil {
-			return ""
+	endpoints := tuiutils.SplitEndpoints(string(*e))
+	for i, endpoint := range endpoints {
+		switch EndpointCLI(endpoint) {
+		case local:
+			endpoints[i] = "http://localhost:4000"
+		case testnet:
+			endpoints[i] = "https://testnet.archethic.net"
+		case mainnet:
+			endpoints[i] = "https://mainnet.archethic.net"
 		}
-		return string(*e)
 	}
+	return strings.Join(endpoints, ",")
 }
 
+// Set accepts a keyword, an url or a comma-separated list of both.
 func (e *EndpointCLI) Set(value string) error {
-	switch value {
-	case "local":
-		*e = "local"
-	case "testnet":
-		*e = "testnet"
-	case "mainnet":
-		*e = "mainnet"
-	default:
-		_, err := url.Parse(string(*e))
-		if err != nil {
-			return errors.New("invalid endpoint value")
+	endpoints := tuiutils.SplitEndpoints(value)
+	if len(endpoints) == 0 {
+		return errors.New("invalid endpoint value")
+	}
+	for _, endpoint := range endpoints {
+		switch EndpointCLI(endpoint) {
+		case local, testnet, mainnet:
+		default:
+			if err := tuiutils.ValidateEndpoint(endpoint); err != nil {
+				return err
+			}
 		}
-		*e = EndpointCLI(value)
 	}
+	*e = EndpointCLI(strings.Join(endpoints, ","))
 	return nil
 }
 
@@ -321,6 +335,123 @@ func (tt *TransactionTypeCLI) GetTransactionType() (archethic.TransactionType, e
 	}
 }
 
+type OutputFormatCLI string
+
+const (
+	TableFormat OutputFormatCLI = "table"
+	JSONFormat  OutputFormatCLI = "json"
+	CSVFormat   OutputFormatCLI = "csv"
+)
+
+func (o *OutputFormatCLI) String() string {
+	return string(*o)
+}
+
+func (o *OutputFormatCLI) Set(value string) error {
+	switch value {
+	case "table":
+		*o = TableFormat
+	case "json":
+		*o = JSONFormat
+	case "csv":
+		*o = CSVFormat
+	default:
+		return errors.New("invalid OutputFormat value")
+	}
+	return nil
+}
+
+func (o *OutputFormatCLI) Type() string {
+	return "OutputFormatCLI"
+}
+
+type ContentEncodingCLI tuiutils.ContentEncoding
+
+func (e *ContentEncodingCLI) String() string {
+	return string(*e)
+}
+
+func (e *ContentEncodingCLI) Set(value string) error {
+	contentEncoding, err := tuiutils.ParseContentEncoding(value)
+	if err != nil {
+		return err
+	}
+	*e = ContentEncodingCLI(contentEncoding)
+	return nil
+}
+
+func (e *ContentEncodingCLI) Type() string {
+	return "ContentEncodingCLI"
+}
+
+type EncodingCLI string
+
+const (
+	HexEncoding    EncodingCLI = "hex"
+	Base64Encoding EncodingCLI = "base64"
+)
+
+func (e *EncodingCLI) String() string {
+	return string(*e)
+}
+
+func (e *EncodingCLI) Set(value string) error {
+	switch value {
+	case "hex":
+		*e = HexEncoding
+	case "base64":
+		*e = Base64Encoding
+	default:
+		return errors.New("invalid Encoding value")
+	}
+	return nil
+}
+
+func (e *EncodingCLI) Type() string {
+	return "EncodingCLI"
+}
+
+func (e *EncodingCLI) Encode(data []byte) string {
+	if *e == Base64Encoding {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	return hex.EncodeToString(data)
+}
+
+func (e *EncodingCLI) Decode(data string) ([]byte, error) {
+	if *e == Base64Encoding {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	return hex.DecodeString(data)
+}
+
+func printKeypairs(w io.Writer, keypairs []tuiutils.DerivedKeypair, format OutputFormatCLI) error {
+	switch format {
+	case JSONFormat:
+		jsonData, err := json.Marshal(keypairs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(jsonData))
+	case CSVFormat:
+		csvWriter := csv.NewWriter(w)
+		csvWriter.Write([]string{"index", "address", "public_key", "curve"})
+		for _, keypair := range keypairs {
+			csvWriter.Write([]string{strconv.FormatUint(uint64(keypair.Index), 10), keypair.Address, keypair.PublicKey, keypair.Curve})
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	default:
+		tabWriter := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tabWriter, "INDEX\tADDRESS\tPUBLIC KEY\tCURVE")
+		for _, keypair := range keypairs {
+			fmt.Fprintf(tabWriter, "%d\t%s\t%s\t%s\n", keypair.Index, keypair.Address, keypair.PublicKey, keypair.Curve)
+		}
+		return tabWriter.Flush()
+	}
+	return nil
+}
+
 func validateRequiredFlags(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedKey, mnemonicFlag string) error {
 	// validate if sshFlagKey or sshPathFlagKey or seedKey is set
 	if !flags.Changed(sshFlagKey) && !flags.Changed(sshPathFlagKey) && !flags.Changed(seedKey) && !flags.Changed(mnemonicFlag) {
@@ -336,7 +467,8 @@ func GetFirstSshKeyDefaultPath() string {
 }
 
 func ToBigInt(number float64, decimals int) uint64 {
-	return uint64(number * math.Pow(10, float64(decimals)))
+	// round to avoid the floating point errors (0.29 * 10^8 = 28999999.999999996)
+	return uint64(math.Round(number * math.Pow(10, float64(decimals))))
 }
 
 func FromBigInt(number uint64, decimals int) float64 {
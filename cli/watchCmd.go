@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	"github.com/spf13/cobra"
+)
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+func GetWatchCmd() *cobra.Command {
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print the new transactions and the received transfers of addresses, seed chains or keychain services",
+		Run: func(cmd *cobra.Command, args []string) {
+			applyProfileEndpoint(cmd)
+			targets, err := getWatchTargets(cmd)
+			cobra.CheckErr(err)
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				cobra.CheckErr(errors.New("the interval must be positive"))
+			}
+			hook, _ := cmd.Flags().GetString("exec")
+			webhook, _ := cmd.Flags().GetString("webhook")
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			err = tuiutils.Watch(ctx, endpoint.String(), targets, interval, func(event tuiutils.WatchEvent) {
+				err := printWatchEvent(os.Stdout, event, outputFormat)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Error:", err)
+				}
+				payload, err := json.Marshal(event)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "Error:", err)
+					return
+				}
+				if hook != "" {
+					err = runHook(hook, payload)
+					if err != nil {
+						fmt.Fprintln(os.Stderr, "Error: hook failed:", err)
+					}
+				}
+				if webhook != "" {
+					err = postWebhook(webhook, payload)
+					if err != nil {
+						fmt.Fprintln(os.Stderr, "Error: webhook failed:", err)
+					}
+				}
+			})
+			cobra.CheckErr(err)
+		},
+	}
+	watchCmd.Flags().Var(&endpoint, "endpoint", "Endpoint (local|testnet|mainnet|[custom url]), or a comma-separated list of them tried in order")
+	watchCmd.Flags().String("profile", "", "Profile of the configuration file providing the default endpoint")
+	watchCmd.Flags().StringSlice("address", []string{}, "Addresses of the chains to watch")
+	setupSeedFlags(watchCmd)
+	watchCmd.Flags().Lookup("access-seed").Usage = "Seed of the chain to watch, or access seed of the keychain when services are given"
+	watchCmd.Flags().StringSlice("service", []string{}, "Services of the keychain to watch")
+	watchCmd.Flags().Duration("interval", 5*time.Second, "Delay between two polls of the chains")
+	watchCmd.Flags().String("exec", "", "Shell command run for each event, with the JSON event on its standard input")
+	watchCmd.Flags().String("webhook", "", "URL receiving each event as a JSON POST request")
+	watchCmd.Flags().Var(&outputFormat, "output", "Output format (table|json)")
+	return watchCmd
+}
+
+// getWatchTargets returns the chains given by the addresses, the seed and the keychain services
+func getWatchTargets(cmd *cobra.Command) ([]tuiutils.WatchTarget, error) {
+	var targets []tuiutils.WatchTarget
+	addresses, _ := cmd.Flags().GetStringSlice("address")
+	for _, address := range addresses {
+		target, err := tuiutils.AddressWatchTarget(address)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	services, _ := cmd.Flags().GetStringSlice("service")
+	if validateRequiredFlags(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic") == nil {
+		seed, err := tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "access-seed", "mnemonic")
+		if err != nil {
+			return nil, err
+		}
+		if len(services) == 0 {
+			curve, err := ellipticCurve.GetCurve()
+			if err != nil {
+				return nil, err
+			}
+			target, err := tuiutils.SeedWatchTarget(seed, curve)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, target)
+		} else {
+			keychain, err := tuiutils.AccessKeychain(endpoint.String(), seed)
+			if err != nil {
+				return nil, err
+			}
+			for _, service := range services {
+				target, err := tuiutils.ServiceWatchTarget(keychain, service)
+				if err != nil {
+					return nil, err
+				}
+				targets = append(targets, target)
+			}
+		}
+	} else if len(services) > 0 {
+		return nil, errors.New("the keychain services require the access seed of the keychain")
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.New("nothing to watch: set an address, a seed or keychain services")
+	}
+	return targets, nil
+}
+
+func printWatchEvent(w io.Writer, event tuiutils.WatchEvent, format OutputFormatCLI) error {
+	if format == JSONFormat {
+		jsonData, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(jsonData))
+		return err
+	}
+
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s %s %s %s", time.Unix(event.Timestamp, 0).UTC().Format(time.RFC3339), event.Target, event.Kind, event.Type)
+	if event.Address != "" {
+		fmt.Fprintf(&line, " %s", event.Address)
+	}
+	if event.From != "" {
+		fmt.Fprintf(&line, " from %s", event.From)
+	}
+	for _, transfer := range event.Transfers {
+		unit := "UCO"
+		if transfer.TokenAddress != "" {
+			unit = fmt.Sprintf("token %s#%d", transfer.TokenAddress, transfer.TokenId)
+		}
+		fmt.Fprintf(&line, ", %s %s to %s", transfer.Amount, unit, transfer.To)
+	}
+	for _, recipient := range event.Recipients {
+		if recipient.Action != "" {
+			fmt.Fprintf(&line, ", calls %s on %s", recipient.Action, recipient.Address)
+		} else {
+			fmt.Fprintf(&line, ", calls %s", recipient.Address)
+		}
+	}
+	_, err := fmt.Fprintln(w, line.String())
+	return err
+}
+
+// runHook runs the shell command with the JSON event on its standard input and in the ARCHETHIC_EVENT variable,
+// with sh -c, or cmd /C on Windows
+func runHook(hook string, payload []byte) error {
+	hookCmd := exec.Command("sh", "-c", hook)
+	if runtime.GOOS == "windows" {
+		hookCmd = exec.Command("cmd", "/C", hook)
+	}
+	hookCmd.Stdin = bytes.NewReader(payload)
+	hookCmd.Stdout = os.Stdout
+	hookCmd.Stderr = os.Stderr
+	hookCmd.Env = append(os.Environ(), "ARCHETHIC_EVENT="+string(payload))
+	return hookCmd.Run()
+}
+
+// postWebhook sends the JSON event to the webhook
+func postWebhook(webhook string, payload []byte) error {
+	response, err := webhookClient.Post(webhook, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfigPath is the environment variable used to override the location of the configuration file
+const EnvConfigPath = "ARCHETHIC_CLI_CONFIG"
+
+// Config is the user configuration of the archethic-cli, read from a YAML file
+type Config struct {
+	DefaultProfile string             `yaml:"default_profile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+	KeyBindings    KeyBindings        `yaml:"keybindings,omitempty"`
+	// Theme is the theme of the TUI: dark, light, high-contrast or no-color
+	Theme string `yaml:"theme,omitempty"`
+	// Contacts maps a name to a contact, whose address is offered by the completion of the address flags
+	// and whose public key can be authorized in the ownerships
+	Contacts map[string]Contact `yaml:"contacts,omitempty"`
+}
+
+// Contact is a known account: its address and the public key to share secrets with.
+// A contact can be written as its address only.
+type Contact struct {
+	Address   string `yaml:"address,omitempty"`
+	PublicKey string `yaml:"public_key,omitempty"`
+}
+
+func (c *Contact) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&c.Address)
+	}
+	type contact Contact
+	return value.Decode((*contact)(c))
+}
+
+// Profile holds the default values of the commands for a given environment
+type Profile struct {
+	// Endpoint used when no endpoint is given (local|testnet|mainnet|[custom url])
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// MaxFee is the maximum fee, in UCO, a transaction can cost to be sent (0 for no limit)
+	MaxFee float64 `yaml:"max_fee,omitempty"`
+}
+
+// KeyBindings configures the key bindings of the TUI:
+// a preset (default|vim) and a list of keys overriding the one of a binding (quit, back, enter, up, down, ...)
+type KeyBindings struct {
+	Preset   string              `yaml:"preset,omitempty"`
+	Bindings map[string][]string `yaml:"bindings,omitempty"`
+}
+
+// Profile returns the profile of the given name, or the default profile if the name is empty.
+// An empty profile is returned when no name is given and there is no default profile.
+func (c Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return Profile{}, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %s", name)
+	}
+	return profile, nil
+}
+
+// DefaultPath returns the location of the configuration file: $ARCHETHIC_CLI_CONFIG or <user config dir>/archethic-cli/config.yaml
+func DefaultPath() string {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "archethic-cli", "config.yaml")
+}
+
+// Load reads the configuration file. A missing file is not an error and returns an empty configuration.
+func Load() (Config, error) {
+	return LoadFile(DefaultPath())
+}
+
+// LoadFile reads the configuration from the given path
+func LoadFile(path string) (Config, error) {
+	var config Config
+	if path == "" {
+		return config, nil
+	}
+	configBytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	err = yaml.Unmarshal(configBytes, &config)
+	if err != nil {
+		return config, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,233 @@
+package logging
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvLogFile is the environment variable used to override the default location of the log file
+const EnvLogFile = "ARCHETHIC_CLI_LOG_FILE"
+
+// Level is the severity of a log line
+type Level int
+
+const (
+	LevelError Level = iota
+	LevelWarn
+	// LevelInfo logs the steps of the commands: the nodes used, the transactions built and sent (-v)
+	LevelInfo
+	// LevelDebug logs every call to the nodes with its duration, and the retries (-vv)
+	LevelDebug
+	// LevelTrace logs the requests sent to the nodes and their responses (-vvv)
+	LevelTrace
+)
+
+var levelNames = map[Level]string{
+	LevelError: "ERROR",
+	LevelWarn:  "WARN",
+	LevelInfo:  "INFO",
+	LevelDebug: "DEBUG",
+	LevelTrace: "TRACE",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// Options configures the logger
+type Options struct {
+	// Verbosity is the number of -v flags: the errors and warnings are always logged, then the info, debug and trace lines
+	Verbosity int
+	// File is the file the lines are appended to. No file is written when empty.
+	File string
+	// Stderr writes the lines to the standard error too, only when the verbosity is set
+	Stderr bool
+}
+
+var logger = struct {
+	sync.Mutex
+	level  Level
+	stderr bool
+	path   string
+	file   *os.File
+}{level: LevelWarn}
+
+// Setup configures the logger from the flags of the command. When verbose, the log file is opened
+// at once so an invalid path is reported, otherwise it is created on the first warning.
+func Setup(options Options) error {
+	logger.Lock()
+	defer logger.Unlock()
+	level := LevelWarn + Level(options.Verbosity)
+	if level > LevelTrace {
+		level = LevelTrace
+	}
+	logger.level = level
+	logger.stderr = options.Stderr && options.Verbosity > 0
+	if logger.file != nil && logger.path != options.File {
+		logger.file.Close()
+		logger.file = nil
+	}
+	logger.path = options.File
+	if level >= LevelTrace {
+		traceNodeCalls()
+	}
+	if options.Verbosity > 0 && logger.path != "" && logger.file == nil {
+		file, err := openLogFile(logger.path)
+		if err != nil {
+			return fmt.Errorf("cannot open the log file: %w", err)
+		}
+		logger.file = file
+	}
+	return nil
+}
+
+// DefaultPath returns the location of the log file of the TUI: $ARCHETHIC_CLI_LOG_FILE, otherwise
+// $XDG_STATE_HOME/archethic-cli/archethic-cli.log (~/.local/state on Linux), otherwise in the user cache directory
+func DefaultPath() string {
+	if path := os.Getenv(EnvLogFile); path != "" {
+		return path
+	}
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" && runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		if home, err := os.UserHomeDir(); err == nil {
+			stateDir = filepath.Join(home, ".local", "state")
+		}
+	}
+	if stateDir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		stateDir = cacheDir
+	}
+	return filepath.Join(stateDir, "archethic-cli", "archethic-cli.log")
+}
+
+// Enabled returns true when the lines of the level are logged
+func Enabled(level Level) bool {
+	logger.Lock()
+	defer logger.Unlock()
+	return level <= logger.level && (logger.stderr || logger.path != "")
+}
+
+// Error logs an error, with the fields given as key value pairs
+func Error(msg string, keyValues ...interface{}) {
+	log(LevelError, msg, keyValues)
+}
+
+// Warn logs a warning, with the fields given as key value pairs
+func Warn(msg string, keyValues ...interface{}) {
+	log(LevelWarn, msg, keyValues)
+}
+
+// Info logs a step of a command, with the fields given as key value pairs
+func Info(msg string, keyValues ...interface{}) {
+	log(LevelInfo, msg, keyValues)
+}
+
+// Debug logs a detail of a command, with the fields given as key value pairs
+func Debug(msg string, keyValues ...interface{}) {
+	log(LevelDebug, msg, keyValues)
+}
+
+// Trace logs a request to a node or its response, with the fields given as key value pairs
+func Trace(msg string, keyValues ...interface{}) {
+	log(LevelTrace, msg, keyValues)
+}
+
+// Writer returns a writer logging each line written at the level, to capture the standard logger
+func Writer(level Level) io.Writer {
+	return lineWriter{level: level}
+}
+
+type lineWriter struct {
+	level Level
+}
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		log(w.level, line, nil)
+	}
+	return len(p), nil
+}
+
+// log writes the line in the logfmt format: time=... level=... msg=... key=value ...
+func log(level Level, msg string, keyValues []interface{}) {
+	if !Enabled(level) {
+		return
+	}
+	var b strings.Builder
+	// the values are redacted before being quoted, so the JSON bodies are redacted as sent
+	fmt.Fprintf(&b, "time=%s level=%s msg=%s", time.Now().Format("2006-01-02T15:04:05.000Z07:00"), level, quote(redact(msg)))
+	for i := 0; i < len(keyValues); i += 2 {
+		key := fmt.Sprint(keyValues[i])
+		value := "(missing)"
+		if i+1 < len(keyValues) {
+			value = formatValue(key, keyValues[i+1])
+		}
+		fmt.Fprintf(&b, " %s=%s", key, quote(redact(value)))
+	}
+	line := b.String() + "\n"
+
+	logger.Lock()
+	defer logger.Unlock()
+	if logger.stderr {
+		os.Stderr.WriteString(line)
+	}
+	if logger.path == "" {
+		return
+	}
+	if logger.file == nil {
+		file, err := openLogFile(logger.path)
+		if err != nil {
+			// the log file must not prevent using the CLI: the logs are dropped
+			logger.path = ""
+			return
+		}
+		logger.file = file
+	}
+	logger.file.WriteString(line)
+}
+
+func openLogFile(path string) (*os.File, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+}
+
+// formatValue formats the value of a field, the values of the secret fields being redacted whatever their type
+func formatValue(key string, value interface{}) string {
+	if isSecretKey(key) {
+		return redacted
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.Round(time.Millisecond).String()
+	case []byte:
+		return strings.ToUpper(hex.EncodeToString(v))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quote quotes the values holding spaces, quotes or equal signs, so the line can be parsed
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces the secrets in the logs
+const redacted = "[REDACTED]"
+
+// secretKeyWords are the words of the names of the fields and of the JSON keys whose values are secret:
+// seeds, mnemonics, passphrases, private keys and the secrets of the ownerships
+var secretKeyWords = []string{"seed", "mnemonic", "passphrase", "password", "secret", "private"}
+
+// secretJSONField matches the JSON string fields whose key holds a secret key word, in the bodies of the requests
+var secretJSONField = regexp.MustCompile(`(?i)("[a-z_]*(?:seed|mnemonic|passphrase|password|secret|private)[a-z_]*"\s*:\s*)"[^"]*"`)
+
+var secrets = struct {
+	sync.Mutex
+	values []string
+}{}
+
+// AddSecret registers values which are redacted wherever they appear in the logs, such as the seeds and
+// the mnemonic words given by the user, which could be quoted by an error message. The values are redacted
+// whatever their length, the user having chosen them as secrets.
+func AddSecret(values ...string) {
+	secrets.Lock()
+	defer secrets.Unlock()
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || containsString(secrets.values, value) {
+			continue
+		}
+		secrets.values = append(secrets.values, value)
+	}
+	// the longest secrets are replaced first, so a short secret held by a longer one doesn't reveal the rest of it
+	sort.SliceStable(secrets.values, func(i, j int) bool {
+		return len(secrets.values[i]) > len(secrets.values[j])
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSecretBytes registers a secret given as bytes, redacted in its hexadecimal forms
+func AddSecretBytes(value []byte) {
+	encoded := hex.EncodeToString(value)
+	AddSecret(encoded, strings.ToUpper(encoded))
+}
+
+// isSecretKey returns true when the field's name designates a secret
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range secretKeyWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact replaces the registered secrets and the secret JSON fields of the value
+func redact(value string) string {
+	value = secretJSONField.ReplaceAllString(value, `${1}"`+redacted+`"`)
+	secrets.Lock()
+	defer secrets.Unlock()
+	for _, secret := range secrets.values {
+		value = strings.ReplaceAll(value, secret, redacted)
+	}
+	return value
+}
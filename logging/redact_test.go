@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShortSecretIsRedacted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	err := Setup(Options{Verbosity: 1, File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Setup(Options{})
+
+	AddSecret("abc1", "abc1xyz9")
+	Info("sending with the seed abc1", "error", "invalid seed abc1xyz9")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := string(content)
+	if strings.Contains(line, "abc1") || strings.Contains(line, "xyz9") {
+		t.Errorf("the secret is logged: %s", line)
+	}
+	if strings.Count(line, redacted) != 2 {
+		t.Errorf("expected 2 %s in %s", redacted, line)
+	}
+}
+
+func TestEmptySecretIsIgnored(t *testing.T) {
+	AddSecret("", "   ")
+	if got := redact("nothing to hide"); got != "nothing to hide" {
+		t.Errorf("redact() = %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxTracedBody is the number of bytes of the bodies of the requests and responses logged
+const maxTracedBody = 4096
+
+var traceOnce sync.Once
+
+// traceNodeCalls wraps the default HTTP transport, used by the clients of the nodes, to trace their requests and responses
+func traceNodeCalls() {
+	traceOnce.Do(func() {
+		http.DefaultTransport = tracingTransport{next: http.DefaultTransport}
+	})
+}
+
+// tracingTransport logs the requests and the responses, whose bodies are read then restored
+type tracingTransport struct {
+	next http.RoundTripper
+}
+
+func (t tracingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if !Enabled(LevelTrace) {
+		return t.next.RoundTrip(request)
+	}
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+	Trace("node request", "method", request.Method, "url", request.URL.String(), "body", truncate(requestBody))
+
+	start := time.Now()
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		Trace("node response", "url", request.URL.String(), "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		Trace("node response", "url", request.URL.String(), "status", response.StatusCode, "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+	Trace("node response", "url", request.URL.String(), "status", response.StatusCode, "duration", time.Since(start), "body", truncate(responseBody))
+	return response, nil
+}
+
+func truncate(body []byte) string {
+	if len(body) > maxTracedBody {
+		return string(body[:maxTracedBody]) + "..."
+	}
+	return string(body)
+}
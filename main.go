@@ -2,7 +2,10 @@ package main
 
 import (
 	"github.com/archethic-foundation/archethic-cli/cli"
+	"github.com/archethic-foundation/archethic-cli/config"
+	"github.com/archethic-foundation/archethic-cli/logging"
 	"github.com/archethic-foundation/archethic-cli/tui"
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	"github.com/spf13/cobra"
 )
@@ -12,11 +15,17 @@ var rootCmd = &cobra.Command{
 	Short: "Archethic CLI",
 	Run: func(cmd *cobra.Command, args []string) {
 		var privateKey []byte
+		cfg, err := config.Load()
+		cobra.CheckErr(err)
+		err = constants.ApplyKeyBindings(cfg.KeyBindings.Preset, cfg.KeyBindings.Bindings)
+		cobra.CheckErr(err)
+		theme, _ := cmd.Flags().GetString("theme")
+		err = constants.ApplyTheme(theme, cfg.Theme)
+		cobra.CheckErr(err)
 		ssh, _ := cmd.Flags().GetBool("ssh")
 		isSshPathSet := cmd.Flag("ssh-path").Changed
 		isSshEnabled := ssh || isSshPathSet
 		if isSshEnabled {
-			var err error
 			privateKey, err = tuiutils.GetSeedBytes(cmd.Flags(), "ssh", "ssh-path", "", "")
 			cobra.CheckErr(err)
 		}
@@ -26,23 +35,79 @@ var rootCmd = &cobra.Command{
 
 func main() {
 	generateAddressCmd := cli.GetGenerateAddressCmd()
+	deriveKeypairCmd := cli.GetDeriveKeypairCmd()
 	sendTransactionCmd := cli.GetSendTransactionCmd()
 	getTransactionFeeCmd := cli.GetGetTransactionFeeCmd()
+	validateTransactionCmd := cli.GetValidateTransactionCmd()
 	createKeychainCmd := cli.GetCreateKeychainCmd()
 	getKeychainCmd := cli.GetKeychainCmd()
 	addServiceToKeychainCmd := cli.GetAddServiceToKeychainCmd()
 	deleteServiceFromKeychainCmd := cli.GetDeleteServiceFromKeychainCmd()
+	readSecretCmd := cli.GetReadSecretCmd()
+	shareSecretCmd := cli.GetShareSecretCmd()
+	sendMessageCmd := cli.GetSendMessageCmd()
+	readMessagesCmd := cli.GetReadMessagesCmd()
+	signMessageCmd := cli.GetSignMessageCmd()
+	verifyMessageCmd := cli.GetVerifyMessageCmd()
+	configSchemaCmd := cli.GetConfigSchemaCmd()
+	nodeInfoCmd := cli.GetNodeInfoCmd()
+	proposeCodeCmd := cli.GetProposeCodeCmd()
+	approveCodeCmd := cli.GetApproveCodeCmd()
+	listProposalsCmd := cli.GetListProposalsCmd()
+	watchCmd := cli.GetWatchCmd()
+	pendingCmd := cli.GetPendingCmd()
+	journalCmd := cli.GetJournalCmd()
+	paymentRequestCmd := cli.GetPaymentRequestCmd()
 
 	rootCmd.AddCommand(generateAddressCmd)
+	rootCmd.AddCommand(deriveKeypairCmd)
 	rootCmd.AddCommand(sendTransactionCmd)
 	rootCmd.AddCommand(getTransactionFeeCmd)
+	rootCmd.AddCommand(validateTransactionCmd)
 	rootCmd.AddCommand(createKeychainCmd)
 	rootCmd.AddCommand(getKeychainCmd)
 	rootCmd.AddCommand(addServiceToKeychainCmd)
 	rootCmd.AddCommand(deleteServiceFromKeychainCmd)
+	rootCmd.AddCommand(readSecretCmd)
+	rootCmd.AddCommand(shareSecretCmd)
+	rootCmd.AddCommand(sendMessageCmd)
+	rootCmd.AddCommand(readMessagesCmd)
+	rootCmd.AddCommand(signMessageCmd)
+	rootCmd.AddCommand(verifyMessageCmd)
+	rootCmd.AddCommand(configSchemaCmd)
+	rootCmd.AddCommand(nodeInfoCmd)
+	rootCmd.AddCommand(proposeCodeCmd)
+	rootCmd.AddCommand(approveCodeCmd)
+	rootCmd.AddCommand(listProposalsCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(pendingCmd)
+	rootCmd.AddCommand(journalCmd)
+	rootCmd.AddCommand(paymentRequestCmd)
 
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		verbosity, _ := cmd.Flags().GetCount("verbose")
+		logFile, _ := cmd.Flags().GetString("log-file")
+		options := logging.Options{Verbosity: verbosity, File: logFile, Stderr: logFile == ""}
+		if cmd == rootCmd {
+			// the TUI occupies the terminal: it only logs to a file
+			options.Stderr = false
+			if logFile == "" {
+				options.File = logging.DefaultPath()
+			}
+		} else {
+			// the transactions sent are recorded in the journal with the command sending them
+			tuiutils.JournalCommand = cmd.Name()
+		}
+		err := logging.Setup(options)
+		cobra.CheckErr(err)
+	}
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Log the steps of the commands (-v), the calls to the nodes (-vv) and their requests and responses (-vvv)")
+	rootCmd.PersistentFlags().String("log-file", "", "File the logs are appended to, instead of the standard error (default to $XDG_STATE_HOME/archethic-cli/archethic-cli.log for the TUI)")
 	rootCmd.Flags().Bool("ssh", false, "Enable SSH key mode")
 	rootCmd.Flags().String("ssh-path", cli.GetFirstSshKeyDefaultPath(), "Path to ssh key")
+	rootCmd.Flags().String("theme", "", "Theme of the TUI (dark|light|high-contrast|no-color), default to the theme of the configuration file, or no-color when NO_COLOR is set")
+	rootCmd.SetGlobalNormalizationFunc(cli.NormalizeFlagName)
+	cli.RegisterCompletions(rootCmd)
 
 	err := rootCmd.Execute()
 	cobra.CheckErr(err)
@@ -1,7 +1,11 @@
 package constants
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -9,27 +13,278 @@ import (
 var DocStyle = lipgloss.NewStyle().Margin(0, 2)
 
 // HelpStyle styling for help context menu
-var HelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render
+func HelpStyle(strs ...string) string {
+	return Theme.Help.Render(strs...)
+}
 
 // ErrStyle provides styling for error messages
-var ErrStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#bd534b")).Render
+func ErrStyle(strs ...string) string {
+	return Theme.Error.Render(strs...)
+}
 
 // AlertStyle provides styling for alert messages
-var AlertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("62")).Render
+func AlertStyle(strs ...string) string {
+	return Theme.Alert.Render(strs...)
+}
 
 type keymap struct {
-	Enter key.Binding
-	Back  key.Binding
+	Enter      key.Binding
+	Back       key.Binding
+	Quit       key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	NextField  key.Binding
+	PrevField  key.Binding
+	NextTab    key.Binding
+	PrevTab    key.Binding
+	Delete     key.Binding
+	Toggle     key.Binding
+	ScrollUp   key.Binding
+	ScrollDown key.Binding
+	Top        key.Binding
+	Bottom     key.Binding
+	ViewUp     key.Binding
+	ViewDown   key.Binding
+	NextPage   key.Binding
+	PrevPage   key.Binding
+	Copy       key.Binding
+	QRCode     key.Binding
+	Refresh    key.Binding
+	Help       key.Binding
 }
 
 // Keymap reusable key mappings shared across models
-var Keymap = keymap{
-	Enter: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	Back: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "back"),
-	),
+var Keymap = defaultKeymap()
+
+func defaultKeymap() keymap {
+	return keymap{
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "back"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("ctrl+c"),
+			key.WithHelp("ctrl+c", "quit"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "previous field"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next field"),
+		),
+		NextField: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		PrevField: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous field"),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("tab", "right"),
+			key.WithHelp("tab/→", "next tab"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("shift+tab", "left"),
+			key.WithHelp("shift+tab/←", "previous tab"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete selected item"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "toggle selection"),
+		),
+		ScrollUp: key.NewBinding(
+			key.WithKeys("pgup"),
+			key.WithHelp("pgup", "scroll up"),
+		),
+		ScrollDown: key.NewBinding(
+			key.WithKeys("pgdown"),
+			key.WithHelp("pgdown", "scroll down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("home"),
+			key.WithHelp("home", "scroll to the top"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("end"),
+			key.WithHelp("end", "scroll to the bottom"),
+		),
+		ViewUp: key.NewBinding(
+			key.WithKeys("shift+up"),
+			key.WithHelp("shift+↑", "scroll the view up"),
+		),
+		ViewDown: key.NewBinding(
+			key.WithKeys("shift+down"),
+			key.WithHelp("shift+↓", "scroll the view down"),
+		),
+		NextPage: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next page"),
+		),
+		PrevPage: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "previous page"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy address"),
+		),
+		QRCode: key.NewBinding(
+			key.WithKeys("q"),
+			key.WithHelp("q", "toggle QR code"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?", "f1"),
+			key.WithHelp("?/f1", "toggle help"),
+		),
+	}
+}
+
+// bindings returns the bindings by their name in the configuration file
+func (k *keymap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"enter":       &k.Enter,
+		"back":        &k.Back,
+		"quit":        &k.Quit,
+		"up":          &k.Up,
+		"down":        &k.Down,
+		"next_field":  &k.NextField,
+		"prev_field":  &k.PrevField,
+		"next_tab":    &k.NextTab,
+		"prev_tab":    &k.PrevTab,
+		"delete":      &k.Delete,
+		"toggle":      &k.Toggle,
+		"scroll_up":   &k.ScrollUp,
+		"scroll_down": &k.ScrollDown,
+		"top":         &k.Top,
+		"bottom":      &k.Bottom,
+		"view_up":     &k.ViewUp,
+		"view_down":   &k.ViewDown,
+		"next_page":   &k.NextPage,
+		"prev_page":   &k.PrevPage,
+		"copy":        &k.Copy,
+		"qr_code":     &k.QRCode,
+		"refresh":     &k.Refresh,
+		"help":        &k.Help,
+	}
+}
+
+// presets are the additional keys of each preset, by binding name
+var presets = map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"up":          {"ctrl+k"},
+		"down":        {"ctrl+j"},
+		"next_tab":    {"ctrl+l"},
+		"prev_tab":    {"ctrl+h"},
+		"scroll_up":   {"ctrl+u"},
+		"scroll_down": {"ctrl+d"},
+		"view_up":     {"ctrl+y"},
+		"view_down":   {"ctrl+e"},
+		"quit":        {"ctrl+q"},
+	},
+}
+
+// plainKeys are the printable keys of each preset standing for another key, only outside of the fields being edited
+// as they are typed in the fields
+var plainKeys = map[string]map[string]tea.KeyType{
+	"vim": {
+		"h": tea.KeyLeft,
+		"j": tea.KeyDown,
+		"k": tea.KeyUp,
+		"l": tea.KeyRight,
+		"g": tea.KeyHome,
+		"G": tea.KeyEnd,
+	},
+}
+
+// presetPlainKeys are the plain keys of the applied preset
+var presetPlainKeys map[string]tea.KeyType
+
+// ApplyKeyBindings resets the Keymap and applies the preset then the overridden keys of the bindings
+func ApplyKeyBindings(preset string, overrides map[string][]string) error {
+	keymap := defaultKeymap()
+	bindings := keymap.bindings()
+
+	if preset == "" {
+		preset = "default"
+	}
+	presetKeys, ok := presets[preset]
+	if !ok {
+		return fmt.Errorf("unknown key bindings preset %s", preset)
+	}
+	for name, keys := range presetKeys {
+		setKeys(bindings[name], append(bindings[name].Keys(), keys...))
+	}
+
+	for name, keys := range overrides {
+		binding, ok := bindings[name]
+		if !ok {
+			return fmt.Errorf("unknown key binding %s", name)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no key provided for the key binding %s", name)
+		}
+		setKeys(binding, keys)
+	}
+
+	Keymap = keymap
+	presetPlainKeys = plainKeys[preset]
+	return nil
+}
+
+// TranslatePlainKey returns the key a plain key of the preset stands for, such as ↓ for j with the vim preset,
+// otherwise the key itself. It must only be called when no field is being edited.
+func TranslatePlainKey(msg tea.KeyMsg) tea.KeyMsg {
+	if msg.Type != tea.KeyRunes || msg.Alt {
+		return msg
+	}
+	keyType, ok := presetPlainKeys[string(msg.Runes)]
+	if !ok {
+		return msg
+	}
+	return tea.KeyMsg{Type: keyType}
+}
+
+func setKeys(binding *key.Binding, keys []string) {
+	binding.SetKeys(keys...)
+	binding.SetHelp(strings.Join(keys, "/"), binding.Help().Desc)
+}
+
+// HelpKeyMap lists the key bindings displayed by the help overlay
+type HelpKeyMap [][]key.Binding
+
+func (h HelpKeyMap) ShortHelp() []key.Binding {
+	var bindings []key.Binding
+	for _, column := range h {
+		bindings = append(bindings, column...)
+	}
+	return bindings
+}
+
+func (h HelpKeyMap) FullHelp() [][]key.Binding {
+	return h
+}
+
+// BackHelp is the help message displayed at the bottom of the views
+func BackHelp() string {
+	return fmt.Sprintf("press '%s' to go back, '%s' for help ", Keymap.Back.Help().Key, Keymap.Help.Help().Key)
+}
+
+// DeleteHelp is the help message displayed under a list of deletable items
+func DeleteHelp(item string) string {
+	return fmt.Sprintf("press '%s' to delete the selected %s ", Keymap.Delete.Help().Key, item)
 }
@@ -0,0 +1,44 @@
+package constants
+
+import "github.com/charmbracelet/lipgloss"
+
+// NarrowWidth is the width of the window under which the views are laid out on a single column
+const NarrowWidth = 80
+
+// minViewportHeight is the smallest number of lines of the scrollable areas
+const minViewportHeight = 3
+
+// Narrow returns true when the views must be laid out on a single column. An unknown width (0) is not narrow.
+func Narrow(width int) bool {
+	return width > 0 && width < NarrowWidth
+}
+
+// Columns lays the blocks out side by side, or one below the other when the width is narrow
+// or too small to hold them on a line
+func Columns(width int, blocks ...string) string {
+	spaced := make([]string, 0, 2*len(blocks))
+	for i, block := range blocks {
+		if i > 0 {
+			spaced = append(spaced, " ")
+		}
+		spaced = append(spaced, block)
+	}
+	row := lipgloss.JoinHorizontal(lipgloss.Top, spaced...)
+	if Narrow(width) || (width > 0 && lipgloss.Width(row) > width) {
+		return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+	}
+	return row
+}
+
+// ViewportHeight returns the height of a scrollable area: the preferred height, reduced to a third
+// of the height of the window on small terminals
+func ViewportHeight(windowHeight int, preferred int) int {
+	height := windowHeight / 3
+	if height > preferred {
+		height = preferred
+	}
+	if height < minViewportHeight {
+		height = minViewportHeight
+	}
+	return height
+}
@@ -0,0 +1,281 @@
+package constants
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme names, as written in the configuration file and given to the --theme flag
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeNoColor      = "no-color"
+)
+
+// theme gathers the styles of the views. The focus is never shown by the colour alone:
+// the focused elements are bold and preceded by the focus marker.
+type theme struct {
+	Name string
+	// FocusMarker precedes the focused button, row or item
+	FocusMarker string
+
+	Focused lipgloss.Style
+	Blurred lipgloss.Style
+	Cursor  lipgloss.Style
+	Help    lipgloss.Style
+	Muted   lipgloss.Style
+	Error   lipgloss.Style
+	Alert   lipgloss.Style
+	Spinner lipgloss.Style
+	// Border is the colour of the borders of the tabs, windows and overlays
+	Border lipgloss.TerminalColor
+
+	Syntax syntaxStyles
+
+	StatusBar lipgloss.Style
+	Mainnet   lipgloss.Style
+	Testnet   lipgloss.Style
+	Local     lipgloss.Style
+	Custom    lipgloss.Style
+}
+
+// syntaxStyles are the styles of the tokens of the smart contracts' code
+type syntaxStyles struct {
+	Keyword    lipgloss.Style
+	Key        lipgloss.Style
+	Module     lipgloss.Style
+	Atom       lipgloss.Style
+	String     lipgloss.Style
+	Number     lipgloss.Style
+	Comment    lipgloss.Style
+	Bracket    lipgloss.Style
+	LineNumber lipgloss.Style
+}
+
+// palette is the colours of a theme
+type palette struct {
+	Focused lipgloss.Color
+	Blurred lipgloss.Color
+	Help    lipgloss.Color
+	Muted   lipgloss.Color
+	Error   lipgloss.Color
+	Alert   lipgloss.Color
+	Border  lipgloss.Color
+	// colours of the smart contracts' code
+	Key    lipgloss.Color
+	Module lipgloss.Color
+	Atom   lipgloss.Color
+	String lipgloss.Color
+	Number lipgloss.Color
+	// backgrounds of the network banners of the status bar
+	Mainnet lipgloss.Color
+	Testnet lipgloss.Color
+	Local   lipgloss.Color
+	Custom  lipgloss.Color
+}
+
+// Theme is the theme of the views, selected by ApplyTheme
+var Theme = darkTheme()
+
+// themes are the available themes by name
+var themes = map[string]func() theme{
+	ThemeDark:         darkTheme,
+	ThemeLight:        lightTheme,
+	ThemeHighContrast: highContrastTheme,
+	ThemeNoColor:      noColorTheme,
+}
+
+func darkTheme() theme {
+	return newTheme(ThemeDark, "▸ ", palette{
+		Focused: "205",
+		Blurred: "240",
+		Help:    "241",
+		Muted:   "244",
+		Error:   "#bd534b",
+		Alert:   "62",
+		Border:  "#874BFD",
+		Key:     "75",
+		Module:  "80",
+		Atom:    "141",
+		String:  "114",
+		Number:  "215",
+		Mainnet: "#bd534b",
+		Testnet: "#e5c07b",
+		Local:   "62",
+		Custom:  "241",
+	})
+}
+
+func lightTheme() theme {
+	return newTheme(ThemeLight, "▸ ", palette{
+		Focused: "162",
+		Blurred: "245",
+		Help:    "243",
+		Muted:   "240",
+		Error:   "#a12a20",
+		Alert:   "25",
+		Border:  "#5a32c8",
+		Key:     "25",
+		Module:  "30",
+		Atom:    "91",
+		String:  "28",
+		Number:  "130",
+		Mainnet: "#a12a20",
+		Testnet: "#e5c07b",
+		Local:   "25",
+		Custom:  "243",
+	})
+}
+
+// highContrastTheme only uses the bright colours of the 16 colours palette, whose rendering is chosen by the terminal
+func highContrastTheme() theme {
+	t := newTheme(ThemeHighContrast, "▶ ", palette{
+		Focused: "11",
+		Blurred: "15",
+		Help:    "15",
+		Muted:   "15",
+		Error:   "9",
+		Alert:   "14",
+		Border:  "15",
+		Key:     "14",
+		Module:  "14",
+		Atom:    "13",
+		String:  "10",
+		Number:  "11",
+		Mainnet: "9",
+		Testnet: "11",
+		Local:   "14",
+		Custom:  "15",
+	})
+	t.Focused = t.Focused.Underline(true)
+	t.Error = t.Error.Bold(true)
+	return t
+}
+
+// noColorTheme relies on the text attributes only: bold and underlined for the focus, faint for the help
+func noColorTheme() theme {
+	return theme{
+		Name:        ThemeNoColor,
+		FocusMarker: ">> ",
+		Focused:     lipgloss.NewStyle().Bold(true).Underline(true),
+		Blurred:     lipgloss.NewStyle(),
+		Cursor:      lipgloss.NewStyle().Reverse(true),
+		Help:        lipgloss.NewStyle().Faint(true),
+		Muted:       lipgloss.NewStyle().Faint(true),
+		Error:       lipgloss.NewStyle().Bold(true),
+		Alert:       lipgloss.NewStyle().Bold(true),
+		Spinner:     lipgloss.NewStyle(),
+		Border:      lipgloss.NoColor{},
+		Syntax: syntaxStyles{
+			Keyword:    lipgloss.NewStyle().Bold(true),
+			Key:        lipgloss.NewStyle(),
+			Module:     lipgloss.NewStyle(),
+			Atom:       lipgloss.NewStyle(),
+			String:     lipgloss.NewStyle(),
+			Number:     lipgloss.NewStyle(),
+			Comment:    lipgloss.NewStyle().Italic(true),
+			Bracket:    lipgloss.NewStyle().Bold(true),
+			LineNumber: lipgloss.NewStyle().Faint(true),
+		},
+		StatusBar: lipgloss.NewStyle().Padding(0, 1),
+		Mainnet:   lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1),
+		Testnet:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
+		Local:     lipgloss.NewStyle().Bold(true).Padding(0, 1),
+		Custom:    lipgloss.NewStyle().Bold(true).Padding(0, 1),
+	}
+}
+
+// newTheme builds the styles of a theme from its colours
+func newTheme(name string, focusMarker string, colors palette) theme {
+	focused := lipgloss.NewStyle().Foreground(colors.Focused).Bold(true)
+	banner := func(background lipgloss.Color, foreground string) lipgloss.Style {
+		return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(foreground)).Background(background).Padding(0, 1)
+	}
+	return theme{
+		Name:        name,
+		FocusMarker: focusMarker,
+		Focused:     focused,
+		Blurred:     lipgloss.NewStyle().Foreground(colors.Blurred),
+		Cursor:      lipgloss.NewStyle().Foreground(colors.Focused),
+		Help:        lipgloss.NewStyle().Foreground(colors.Help),
+		Muted:       lipgloss.NewStyle().Foreground(colors.Muted),
+		Error:       lipgloss.NewStyle().Foreground(colors.Error),
+		Alert:       lipgloss.NewStyle().Foreground(colors.Alert),
+		Spinner:     lipgloss.NewStyle().Foreground(colors.Focused),
+		Border:      colors.Border,
+		Syntax: syntaxStyles{
+			Keyword:    lipgloss.NewStyle().Foreground(colors.Focused).Bold(true),
+			Key:        lipgloss.NewStyle().Foreground(colors.Key),
+			Module:     lipgloss.NewStyle().Foreground(colors.Module),
+			Atom:       lipgloss.NewStyle().Foreground(colors.Atom),
+			String:     lipgloss.NewStyle().Foreground(colors.String),
+			Number:     lipgloss.NewStyle().Foreground(colors.Number),
+			Comment:    lipgloss.NewStyle().Foreground(colors.Help).Italic(true),
+			Bracket:    lipgloss.NewStyle().Foreground(colors.Focused),
+			LineNumber: lipgloss.NewStyle().Foreground(colors.Help),
+		},
+		StatusBar: lipgloss.NewStyle().Foreground(colors.Help).Padding(0, 1),
+		Mainnet:   banner(colors.Mainnet, "#FFFFFF"),
+		Testnet:   banner(colors.Testnet, "#000000"),
+		Local:     banner(colors.Local, "#FFFFFF"),
+		Custom:    banner(colors.Custom, "#FFFFFF"),
+	}
+}
+
+// ThemeNames returns the names of the available themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTheme selects the theme: the one given by the flag, otherwise the one of the configuration file,
+// otherwise the no-colour theme when NO_COLOR is set (https://no-color.org), otherwise the dark theme
+func ApplyTheme(flagTheme string, configTheme string) error {
+	name := flagTheme
+	if name == "" {
+		name = configTheme
+	}
+	if name == "" && os.Getenv("NO_COLOR") != "" {
+		name = ThemeNoColor
+	}
+	if name == "" {
+		name = ThemeDark
+	}
+	newTheme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %s", name)
+	}
+	Theme = newTheme()
+	if name == ThemeNoColor {
+		// the colours of the bubbles components (menu, help) are also removed
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else if name == ThemeLight {
+		lipgloss.SetHasDarkBackground(false)
+	}
+	return nil
+}
+
+// Button renders a button, the focused one being preceded by the focus marker
+func (t theme) Button(label string, focused bool) string {
+	if focused {
+		return t.Focused.Render(t.FocusMarker + "[ " + label + " ]")
+	}
+	return fmt.Sprintf("%*s[ %s ]", lipgloss.Width(t.FocusMarker), "", t.Blurred.Render(label))
+}
+
+// Row renders a row of a list, the selected one being preceded by the focus marker
+func (t theme) Row(line string, selected bool) string {
+	if selected {
+		return t.Focused.Render(t.FocusMarker + line)
+	}
+	return fmt.Sprintf("%*s%s", lipgloss.Width(t.FocusMarker), "", line)
+}
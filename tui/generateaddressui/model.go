@@ -7,9 +7,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -17,51 +20,58 @@ import (
 // BackMsg change state back to project view
 type BackMsg bool
 
-var (
-	focusedStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	blurredStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	cursorStyle         = focusedStyle.Copy()
-	noStyle             = lipgloss.NewStyle()
-	helpStyle           = blurredStyle.Copy()
-	cursorModeHelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
-
-	focusedButton = focusedStyle.Copy().Render("[ Submit ]")
-	blurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Submit"))
+const (
+	SEED_INDEX      = 0
+	INDEX_INDEX     = 1
+	TO_INDEX_INDEX  = 2
+	CURVE_INDEX     = 3
+	HASH_ALGO_INDEX = 4
 )
 
+// resultsHeight is the height of the list of the generated addresses on a large terminal
+const resultsHeight = 10
+
 type Model struct {
 	focusIndex       int
 	inputs           []textinput.Model
 	generatedAddress string
+	showQRCode       bool
+	keypairs         []tuiutils.DerivedKeypair
+	results          viewport.Model
 	feedback         string
 }
 
 func New() Model {
 	m := Model{
-		inputs: make([]textinput.Model, 4),
+		inputs:  make([]textinput.Model, 5),
+		results: viewport.New(150, resultsHeight),
 	}
 
 	var t textinput.Model
 	for i := range m.inputs {
 		t = textinput.New()
-		t.CursorStyle = cursorStyle
+		t.CursorStyle = constants.Theme.Cursor
 
 		switch i {
-		case 0:
+		case SEED_INDEX:
 			t.Prompt = "> Key generation seed:\n"
 			t.Focus()
 			t.EchoMode = textinput.EchoPassword
 			t.EchoCharacter = '•'
-		case 1:
+		case INDEX_INDEX:
 			t.Prompt = "> Index of key to generate\n"
 			t.Placeholder = "(default 0)"
 			t.Validate = numberValidator
-		case 2:
+		case TO_INDEX_INDEX:
+			t.Prompt = "> Last index of the range to generate\n"
+			t.Placeholder = "(optional)"
+			t.Validate = optionalNumberValidator
+		case CURVE_INDEX:
 			t.Prompt = "> Elliptic curve\n"
 			t.Placeholder = "(default 0)"
 			t.CharLimit = 1
 			t.Validate = curveValidator
-		case 3:
+		case HASH_ALGO_INDEX:
 			t.Prompt = "> Hash algorithm\n"
 			t.Placeholder = "(default 0)"
 			t.CharLimit = 1
@@ -99,41 +109,72 @@ func numberValidator(s string) error {
 	return err
 }
 
+func optionalNumberValidator(s string) error {
+	if s == "" {
+		return nil
+	}
+	return numberValidator(s)
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.results.Width = msg.Width
+		m.results.Height = constants.ViewportHeight(msg.Height, resultsHeight)
+		return m, nil
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		switch {
+		case key.Matches(msg, constants.Keymap.Quit):
 			return m, tea.Quit
 
-		case "esc":
+		// Scroll the list of generated addresses
+		case key.Matches(msg, constants.Keymap.ScrollUp):
+			m.results.ViewUp()
+			return m, nil
+		case key.Matches(msg, constants.Keymap.ScrollDown):
+			m.results.ViewDown()
+			return m, nil
+		case key.Matches(msg, constants.Keymap.Top) && !m.Editing():
+			m.results.GotoTop()
+			return m, nil
+		case key.Matches(msg, constants.Keymap.Bottom) && !m.Editing():
+			m.results.GotoBottom()
+			return m, nil
+
+		// Display the generated address as a QR code, to receive a payment from a phone
+		case key.Matches(msg, constants.Keymap.QRCode) && !m.Editing() && m.generatedAddress != "":
+			m.showQRCode = !m.showQRCode
+			return m, nil
+
+		case key.Matches(msg, constants.Keymap.Back):
 			return New(), func() tea.Msg {
 				return BackMsg(true)
 			}
 
 		// Set focus to next input
-		case "tab", "shift+tab", "enter", "up", "down":
-			s := msg.String()
-
+		case key.Matches(msg, constants.Keymap.NextField, constants.Keymap.PrevField, constants.Keymap.Enter, constants.Keymap.Up, constants.Keymap.Down):
 			// Did the user press enter while the submit button was focused?
 			// If so, exit.
-			if s == "enter" && m.focusIndex == len(m.inputs) {
-				seed, err := archethic.MaybeConvertToHex(m.inputs[0].Value())
+			if key.Matches(msg, constants.Keymap.Enter) && m.focusIndex == len(m.inputs) {
+				m.feedback = ""
+				m.generatedAddress = ""
+				m.keypairs = nil
+				seed, err := tuiutils.DecodeSecret(m.inputs[SEED_INDEX].Value())
 				if err != nil {
 					m.feedback = err.Error()
 					return m, nil
 				}
-				index, err := strconv.ParseUint(m.inputs[1].Value(), 10, 32)
+				index, err := strconv.ParseUint(m.inputs[INDEX_INDEX].Value(), 10, 32)
 
 				// check for errors
 				if err != nil {
 					index = 0
 				}
-				curveInt, err := strconv.ParseUint(m.inputs[2].Value(), 10, 8)
+				curveInt, err := strconv.ParseUint(m.inputs[CURVE_INDEX].Value(), 10, 8)
 				if err != nil {
 					curveInt = 0
 				}
-				hashAlgoInt, err := strconv.ParseUint(m.inputs[3].Value(), 10, 8)
+				hashAlgoInt, err := strconv.ParseUint(m.inputs[HASH_ALGO_INDEX].Value(), 10, 8)
 				if err != nil {
 					hashAlgoInt = 0
 				}
@@ -141,16 +182,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				curve := archethic.Curve(uint8(curveInt))
 				hashAlgo := archethic.HashAlgo(uint8(hashAlgoInt))
 
-				address, err := archethic.DeriveAddress(seed, uint32(index), curve, hashAlgo)
-				if err != nil {
-					m.feedback = err.Error()
+				// a range is requested: display the list of keypairs
+				if m.inputs[TO_INDEX_INDEX].Value() != "" {
+					toIndex, err := strconv.ParseUint(m.inputs[TO_INDEX_INDEX].Value(), 10, 32)
+					if err != nil {
+						m.feedback = "Invalid last index"
+						return m, nil
+					}
+					// the range is checked before deriving it, a huge range would exhaust the memory
+					if err := tuiutils.CheckIndexRange(uint32(index), uint32(toIndex)); err != nil {
+						m.feedback = err.Error()
+						return m, nil
+					}
+					keypairs, err := tuiutils.DeriveKeypairs(seed, uint32(index), uint32(toIndex), curve, hashAlgo)
+					if err != nil {
+						m.feedback = err.Error()
+						return m, nil
+					}
+					m.keypairs = keypairs
+					m.results.SetContent(keypairsView(m.keypairs))
+					m.results.GotoTop()
 				} else {
-					m.generatedAddress = hex.EncodeToString(address)
+					address, err := archethic.DeriveAddress(seed, uint32(index), curve, hashAlgo)
+					if err != nil {
+						m.feedback = err.Error()
+					} else {
+						m.generatedAddress = hex.EncodeToString(address)
+					}
 				}
 			}
 
 			// Cycle indexes
-			if s == "up" || s == "shift+tab" {
+			if key.Matches(msg, constants.Keymap.Up, constants.Keymap.PrevField) {
 				m.focusIndex--
 			} else {
 				m.focusIndex++
@@ -167,14 +230,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if i == m.focusIndex {
 					// Set focused state
 					cmds[i] = m.inputs[i].Focus()
-					// m.inputs[i].PromptStyle = focusedStyle
-					// m.inputs[i].TextStyle = focusedStyle
+					// m.inputs[i].PromptStyle = constants.Theme.Focused
+					// m.inputs[i].TextStyle = constants.Theme.Focused
 					continue
 				}
 				// Remove focused state
 				m.inputs[i].Blur()
-				m.inputs[i].PromptStyle = noStyle
-				m.inputs[i].TextStyle = noStyle
+				m.inputs[i].PromptStyle = lipgloss.NewStyle()
+				m.inputs[i].TextStyle = lipgloss.NewStyle()
 			}
 
 			return m, tea.Batch(cmds...)
@@ -202,13 +265,13 @@ func (m Model) View() string {
 
 	for i := range m.inputs {
 		b.WriteString(m.inputs[i].View())
-		if i == 2 {
+		if i == CURVE_INDEX {
 			b.WriteString("\n")
 			for j := 0; j <= 2; j++ {
 				b.WriteString("\t (" + strconv.Itoa(j) + ") " + tuiutils.GetCurveName(archethic.Curve(j)) + "\n")
 			}
 		}
-		if i == 3 {
+		if i == HASH_ALGO_INDEX {
 			b.WriteString("\n")
 			for j := 0; j <= 4; j++ {
 				b.WriteString("\t (" + strconv.Itoa(j) + ") " + tuiutils.GetHashAlgorithmName(archethic.HashAlgo(j)) + "\n")
@@ -219,11 +282,8 @@ func (m Model) View() string {
 		}
 	}
 
-	button := &blurredButton
-	if m.focusIndex == len(m.inputs) {
-		button = &focusedButton
-	}
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+	button := constants.Theme.Button("Submit", m.focusIndex == len(m.inputs))
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
 
 	if m.feedback != "" {
 		b.WriteString(m.feedback + "\n\n")
@@ -231,9 +291,55 @@ func (m Model) View() string {
 
 	if m.generatedAddress != "" {
 		b.WriteString("The generated address is: " + m.generatedAddress)
+		if m.showQRCode {
+			b.WriteString("\n\n" + qrCodeView(m.generatedAddress))
+		} else {
+			b.WriteString("\n" + constants.Theme.Help.Render(fmt.Sprintf("press '%s' on the Submit button to display it as a QR code", constants.Keymap.QRCode.Help().Key)))
+		}
+	}
+
+	if len(m.keypairs) > 0 {
+		fmt.Fprintf(&b, "Generated keypairs (%d):\n", len(m.keypairs))
+		b.WriteString(m.results.View())
+		b.WriteString("\n")
+		b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("%3.f%% - press '%s'/'%s' to scroll ", m.results.ScrollPercent()*100, constants.Keymap.ScrollUp.Help().Key, constants.Keymap.ScrollDown.Help().Key)))
 	}
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("press 'esc' to go back "))
+	b.WriteString(constants.Theme.Help.Render(constants.BackHelp()))
 
 	return b.String()
 }
+
+// Editing returns true when the user is typing in a field
+func (m Model) Editing() bool {
+	return m.focusIndex < len(m.inputs)
+}
+
+// KeyMap returns the key bindings displayed in the help overlay
+func (m Model) KeyMap() constants.HelpKeyMap {
+	return constants.HelpKeyMap{
+		{constants.Keymap.Up, constants.Keymap.Down, constants.Keymap.NextField, constants.Keymap.PrevField},
+		{constants.Keymap.Enter, constants.Keymap.QRCode, constants.Keymap.ScrollUp, constants.Keymap.ScrollDown, constants.Keymap.Top, constants.Keymap.Bottom},
+		{constants.Keymap.Back, constants.Keymap.Help, constants.Keymap.Quit},
+	}
+}
+
+// qrCodeView renders the payment request URI of the address, inverted on the light theme
+func qrCodeView(address string) string {
+	request := tuiutils.PaymentRequest{Address: strings.ToUpper(address)}
+	code, err := tuiutils.PaymentQRCode(request.URI(), constants.Theme.Name == constants.ThemeLight)
+	if err != nil {
+		return constants.ErrStyle(err.Error())
+	}
+	return code + request.URI()
+}
+
+func keypairsView(keypairs []tuiutils.DerivedKeypair) string {
+	var b strings.Builder
+	for _, keypair := range keypairs {
+		fmt.Fprintf(&b, "#%d (%s)\n", keypair.Index, keypair.Curve)
+		fmt.Fprintf(&b, "  Address:    %s\n", keypair.Address)
+		fmt.Fprintf(&b, "  Public key: %s\n", keypair.PublicKey)
+	}
+	return b.String()
+}
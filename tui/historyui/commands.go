@@ -0,0 +1,57 @@
+package historyui
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadedChainCmd prefills the form with the chain used to build the last transaction
+func loadedChainCmd() tea.Msg {
+	chain, ok := tuiutils.GetLoadedChain()
+	if !ok {
+		return nil
+	}
+	return loadedChainMsg{chain: chain}
+}
+
+// loadChainCmd resolves the genesis address of the chain and fetches its first page
+func loadChainCmd(endpoint string, seed []byte, curve archethic.Curve, serviceName string) tea.Cmd {
+	return func() tea.Msg {
+		genesisAddress, err := tuiutils.GenesisAddress(endpoint, seed, curve, serviceName)
+		if err != nil {
+			return pageFetchedMsg{err: err}
+		}
+		return fetchPage(endpoint, strings.ToUpper(hex.EncodeToString(genesisAddress)), []string{""})
+	}
+}
+
+// fetchPageCmd fetches the last page of the pages stack
+func fetchPageCmd(endpoint string, chainAddress string, pages []string) tea.Cmd {
+	return func() tea.Msg {
+		return fetchPage(endpoint, chainAddress, pages)
+	}
+}
+
+func fetchPage(endpoint string, chainAddress string, pages []string) pageFetchedMsg {
+	transactions, err := tuiutils.GetTransactionChain(endpoint, chainAddress, pages[len(pages)-1])
+	return pageFetchedMsg{chainAddress: chainAddress, pages: pages, transactions: transactions, err: err}
+}
+
+func fetchTransactionCmd(endpoint string, address string) tea.Cmd {
+	return func() tea.Msg {
+		transaction, err := tuiutils.GetTransaction(endpoint, address)
+		return transactionFetchedMsg{transaction: transaction, err: err}
+	}
+}
+
+// refreshSessionCmd periodically refreshes the status of the transactions sent during the session
+func refreshSessionCmd(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return refreshSessionMsg{id: id}
+	})
+}
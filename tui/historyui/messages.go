@@ -0,0 +1,26 @@
+package historyui
+
+import "github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+
+// BackMsg change state back to project view
+type BackMsg bool
+
+type loadedChainMsg struct {
+	chain tuiutils.LoadedChain
+}
+
+type pageFetchedMsg struct {
+	chainAddress string
+	pages        []string
+	transactions []tuiutils.TransactionGQL
+	err          error
+}
+
+type transactionFetchedMsg struct {
+	transaction *tuiutils.TransactionGQL
+	err         error
+}
+
+type refreshSessionMsg struct {
+	id int
+}
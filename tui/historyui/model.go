@@ -0,0 +1,657 @@
+package historyui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type historyTab int
+
+const (
+	CHAIN_TAB   historyTab = 0
+	SESSION_TAB historyTab = 1
+	JOURNAL_TAB historyTab = 2
+)
+
+const (
+	URL_INDEX          = 0
+	SEED_INDEX         = 1
+	CURVE_INDEX        = 2
+	SERVICE_NAME_INDEX = 3
+	LOAD_BUTTON_INDEX  = 4
+	LIST_INDEX         = 5
+)
+
+// the node returns the transaction chain by pages of 10 transactions
+const pageSize = 10
+
+// detailsHeight is the height of the details of the selected transaction on a large terminal
+const detailsHeight = 12
+
+// lastRefreshID identifies the refresh loop of the session tab, so the loop of a previous model stops
+var lastRefreshID int
+
+type Model struct {
+	Tabs                []string
+	activeTab           historyTab
+	focusIndex          int
+	inputs              []textinput.Model
+	pvKeyBytes          []byte
+	loadedSeed          []byte
+	endpoint            string
+	chainAddress        string
+	pages               []string
+	transactions        []tuiutils.TransactionGQL
+	sessionTransactions []tuiutils.SentTransaction
+	journalEntries      []tuiutils.JournalEntry
+	cursor              int
+	details             viewport.Model
+	feedback            string
+	showSpinner         bool
+	Spinner             spinner.Model
+	refreshID           int
+	IsInit              bool
+}
+
+func New(pvKeyBytes []byte) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = constants.Theme.Spinner
+	lastRefreshID++
+	m := Model{
+		Tabs:       []string{"Chain", "Session", "Journal"},
+		activeTab:  CHAIN_TAB,
+		inputs:     make([]textinput.Model, 4),
+		pvKeyBytes: pvKeyBytes,
+		details:    viewport.New(150, detailsHeight),
+		Spinner:    s,
+		refreshID:  lastRefreshID,
+	}
+
+	for i := range m.inputs {
+		t := textinput.New()
+		t.CursorStyle = constants.Theme.Cursor
+		switch i {
+		case URL_INDEX:
+			t.Prompt = "> Node endpoint\n"
+			t.Placeholder = "https://mainnet.archethic.net"
+			t.Focus()
+		case SEED_INDEX:
+			t.Prompt = "> Seed\n"
+			if pvKeyBytes != nil {
+				t.Placeholder = "(Imported SSH key)"
+			}
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+		case CURVE_INDEX:
+			t.Prompt = "> Elliptic curve\n"
+			t.Placeholder = "(default 0)"
+			t.CharLimit = 1
+			t.Validate = curveValidator
+		case SERVICE_NAME_INDEX:
+			t.Prompt = "> Keychain service name\n"
+			t.Placeholder = "(optional, the seed is then the keychain access seed)"
+		}
+		m.inputs[i] = t
+	}
+	return m
+}
+
+func curveValidator(s string) error {
+	val, err := strconv.ParseInt(s, 10, 32)
+	if err == nil && (val < 0 || val > 2) {
+		return errors.New("number should be >0 and <=2")
+	}
+	return err
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.Spinner.Tick, loadedChainCmd, refreshSessionCmd(m.refreshID))
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.details.Width = msg.Width - constants.DocStyle.GetHorizontalFrameSize()
+		m.details.Height = constants.ViewportHeight(msg.Height, detailsHeight)
+		return m, nil
+	case loadedChainMsg:
+		m.inputs[URL_INDEX].SetValue(msg.chain.Endpoint)
+		m.inputs[CURVE_INDEX].SetValue(fmt.Sprint(msg.chain.Curve))
+		m.inputs[SERVICE_NAME_INDEX].SetValue(msg.chain.ServiceName)
+		m.inputs[SEED_INDEX].Placeholder = "(seed of the last transaction built)"
+		m.loadedSeed = msg.chain.Seed
+		return m.loadChain()
+	case pageFetchedMsg:
+		m.showSpinner = false
+		if msg.err != nil {
+			m.feedback = msg.err.Error()
+			return m, nil
+		}
+		// the previous page was the last one
+		if len(msg.transactions) == 0 && len(msg.pages) > 1 {
+			m.feedback = "No more transactions"
+			return m, nil
+		}
+		m.chainAddress = msg.chainAddress
+		m.pages = msg.pages
+		m.transactions = msg.transactions
+		m.cursor = 0
+		m.focusIndex = LIST_INDEX
+		m.updateFocus()
+		m.updateDetails()
+		return m, nil
+	case transactionFetchedMsg:
+		m.showSpinner = false
+		if msg.err != nil {
+			m.feedback = msg.err.Error()
+			return m, nil
+		}
+		m.details.SetContent(transactionView(*msg.transaction))
+		m.details.GotoTop()
+		return m, nil
+	case refreshSessionMsg:
+		if msg.id != m.refreshID {
+			return m, nil
+		}
+		m.sessionTransactions = tuiutils.SentTransactions()
+		if m.cursor >= len(m.sessionTransactions) && m.activeTab == SESSION_TAB {
+			m.cursor = max(len(m.sessionTransactions)-1, 0)
+		}
+		return m, refreshSessionCmd(m.refreshID)
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, constants.Keymap.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, constants.Keymap.Back):
+			return New(m.pvKeyBytes), func() tea.Msg {
+				return BackMsg(true)
+			}
+		case key.Matches(msg, constants.Keymap.ScrollUp):
+			m.details.ViewUp()
+			return m, nil
+		case key.Matches(msg, constants.Keymap.ScrollDown):
+			m.details.ViewDown()
+			return m, nil
+		case key.Matches(msg, constants.Keymap.Top) && !m.Editing():
+			m.details.GotoTop()
+			return m, nil
+		case key.Matches(msg, constants.Keymap.Bottom) && !m.Editing():
+			m.details.GotoBottom()
+			return m, nil
+		case key.Matches(msg, constants.Keymap.NextTab, constants.Keymap.PrevTab):
+			step := 1
+			if key.Matches(msg, constants.Keymap.PrevTab) {
+				step = len(m.Tabs) - 1
+			}
+			m.activeTab = historyTab((int(m.activeTab) + step) % len(m.Tabs))
+			m.cursor = 0
+			m.feedback = ""
+			m.details.SetContent("")
+			switch m.activeTab {
+			case CHAIN_TAB:
+				m.updateDetails()
+			case SESSION_TAB:
+				m.sessionTransactions = tuiutils.SentTransactions()
+			case JOURNAL_TAB:
+				m.loadJournal()
+			}
+			return m, m.updateFocus()
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
+			return m.moveFocus(key.Matches(msg, constants.Keymap.Up))
+		case key.Matches(msg, constants.Keymap.Enter):
+			if m.activeTab == JOURNAL_TAB {
+				if m.cursor < len(m.journalEntries) {
+					m.details.SetContent(journalEntryView(m.journalEntries[m.cursor]))
+					m.details.GotoTop()
+				}
+				return m, nil
+			}
+			if m.activeTab == SESSION_TAB {
+				if m.cursor < len(m.sessionTransactions) {
+					transaction := m.sessionTransactions[m.cursor]
+					m.showSpinner = true
+					m.feedback = ""
+					return m, fetchTransactionCmd(transaction.Endpoint, transaction.Address)
+				}
+				return m, nil
+			}
+			if m.focusIndex == LOAD_BUTTON_INDEX {
+				return m.loadChain()
+			}
+			if m.focusIndex < LOAD_BUTTON_INDEX {
+				return m.moveFocus(false)
+			}
+			return m, nil
+		}
+
+		// the following keys are typed in the inputs while the form is focused
+		if m.listFocused() {
+			switch {
+			case key.Matches(msg, constants.Keymap.Copy):
+				address := m.selectedAddress()
+				if address == "" {
+					return m, nil
+				}
+				if err := clipboard.WriteAll(address); err != nil {
+					m.feedback = err.Error()
+				} else {
+					m.feedback = "Address " + address + " copied to the clipboard"
+				}
+				return m, nil
+			case key.Matches(msg, constants.Keymap.Refresh):
+				if m.activeTab == JOURNAL_TAB {
+					m.loadJournal()
+					return m, nil
+				}
+				if m.activeTab == SESSION_TAB {
+					m.sessionTransactions = tuiutils.SentTransactions()
+					return m, nil
+				}
+				if len(m.pages) > 0 {
+					m.showSpinner = true
+					return m, fetchPageCmd(m.endpoint, m.chainAddress, m.pages)
+				}
+				return m, nil
+			case key.Matches(msg, constants.Keymap.NextPage):
+				if m.activeTab == CHAIN_TAB && len(m.transactions) == pageSize {
+					pages := append(append([]string{}, m.pages...), m.transactions[len(m.transactions)-1].Address)
+					m.showSpinner = true
+					return m, fetchPageCmd(m.endpoint, m.chainAddress, pages)
+				}
+				return m, nil
+			case key.Matches(msg, constants.Keymap.PrevPage):
+				if m.activeTab == CHAIN_TAB && len(m.pages) > 1 {
+					m.showSpinner = true
+					return m, fetchPageCmd(m.endpoint, m.chainAddress, m.pages[:len(m.pages)-1])
+				}
+				return m, nil
+			}
+			return m, nil
+		}
+	default:
+		var cmd tea.Cmd
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		return m, cmd
+	}
+
+	// Handle character input
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// loadChain fetches the first page of the chain described by the form
+func (m Model) loadChain() (tea.Model, tea.Cmd) {
+	m.feedback = ""
+	m.endpoint = m.inputs[URL_INDEX].Value()
+	if m.endpoint == "" {
+		m.feedback = "The node endpoint is required"
+		return m, nil
+	}
+	var seed []byte
+	switch {
+	case m.inputs[SEED_INDEX].Value() != "":
+		var err error
+		seed, err = tuiutils.DecodeSecret(m.inputs[SEED_INDEX].Value())
+		if err != nil {
+			m.feedback = err.Error()
+			return m, nil
+		}
+	case m.loadedSeed != nil:
+		seed = m.loadedSeed
+	case m.pvKeyBytes != nil:
+		seed = m.pvKeyBytes
+	default:
+		m.feedback = "The seed is required"
+		return m, nil
+	}
+	curveInt, err := strconv.Atoi(m.inputs[CURVE_INDEX].Value())
+	if err != nil {
+		curveInt = 0
+	}
+	m.showSpinner = true
+	return m, loadChainCmd(m.endpoint, seed, archethic.Curve(curveInt), m.inputs[SERVICE_NAME_INDEX].Value())
+}
+
+// loadJournal reads the journal of the transactions sent, the most recent first
+func (m *Model) loadJournal() {
+	entries, err := tuiutils.ReadJournal()
+	if err != nil {
+		m.feedback = err.Error()
+	}
+	m.journalEntries = make([]tuiutils.JournalEntry, len(entries))
+	for i, entry := range entries {
+		m.journalEntries[len(entries)-1-i] = entry
+	}
+}
+
+func (m Model) listFocused() bool {
+	return m.activeTab != CHAIN_TAB || m.focusIndex == LIST_INDEX
+}
+
+// moveFocus moves the focus between the fields of the form and the rows of the list
+func (m Model) moveFocus(up bool) (tea.Model, tea.Cmd) {
+	if m.activeTab != CHAIN_TAB {
+		length := len(m.sessionTransactions)
+		if m.activeTab == JOURNAL_TAB {
+			length = len(m.journalEntries)
+		}
+		if up && m.cursor > 0 {
+			m.cursor--
+		} else if !up && m.cursor < length-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
+	if m.focusIndex == LIST_INDEX {
+		switch {
+		case up && m.cursor == 0:
+			m.focusIndex = LOAD_BUTTON_INDEX
+		case up:
+			m.cursor--
+		case m.cursor < len(m.transactions)-1:
+			m.cursor++
+		}
+		m.updateDetails()
+		return m, m.updateFocus()
+	}
+
+	if up {
+		m.focusIndex--
+	} else {
+		m.focusIndex++
+	}
+	if m.focusIndex < 0 {
+		m.focusIndex = LOAD_BUTTON_INDEX
+	} else if m.focusIndex > LOAD_BUTTON_INDEX && len(m.transactions) == 0 {
+		m.focusIndex = 0
+	}
+	return m, m.updateFocus()
+}
+
+func (m *Model) updateFocus() tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		if i == m.focusIndex && m.activeTab == CHAIN_TAB {
+			cmds[i] = m.inputs[i].Focus()
+			continue
+		}
+		m.inputs[i].Blur()
+		m.inputs[i].PromptStyle = lipgloss.NewStyle()
+		m.inputs[i].TextStyle = lipgloss.NewStyle()
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateDetails displays the selected transaction of the chain in the details pane
+func (m *Model) updateDetails() {
+	if m.cursor < len(m.transactions) {
+		m.details.SetContent(transactionView(m.transactions[m.cursor]))
+	} else {
+		m.details.SetContent("")
+	}
+	m.details.GotoTop()
+}
+
+func (m Model) selectedAddress() string {
+	if m.activeTab == JOURNAL_TAB {
+		if m.cursor < len(m.journalEntries) {
+			return m.journalEntries[m.cursor].Address
+		}
+		return ""
+	}
+	if m.activeTab == SESSION_TAB {
+		if m.cursor < len(m.sessionTransactions) {
+			return m.sessionTransactions[m.cursor].Address
+		}
+		return ""
+	}
+	if m.cursor < len(m.transactions) {
+		return m.transactions[m.cursor].Address
+	}
+	return ""
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	for i, t := range m.Tabs {
+		if i == int(m.activeTab) {
+			b.WriteString(constants.Theme.Focused.Render("[ " + t + " ]"))
+		} else {
+			b.WriteString(constants.Theme.Blurred.Render("  " + t + "  "))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString("\n\n")
+
+	switch m.activeTab {
+	case CHAIN_TAB:
+		b.WriteString(m.chainView())
+	case SESSION_TAB:
+		b.WriteString(m.sessionView())
+	case JOURNAL_TAB:
+		b.WriteString(m.journalView())
+	}
+
+	if m.showSpinner {
+		b.WriteString(m.Spinner.View() + "\n\n")
+	}
+	if m.feedback != "" {
+		b.WriteString(m.feedback + "\n\n")
+	}
+
+	if m.details.TotalLineCount() > 1 {
+		b.WriteString("> Details:\n")
+		b.WriteString(m.details.View())
+		b.WriteString("\n")
+		b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("%3.f%% - press '%s'/'%s' to scroll ", m.details.ScrollPercent()*100, constants.Keymap.ScrollUp.Help().Key, constants.Keymap.ScrollDown.Help().Key)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("press '%s' to copy the selected address, '%s' to refresh", constants.Keymap.Copy.Help().Key, constants.Keymap.Refresh.Help().Key)))
+	b.WriteString("\n")
+	b.WriteString(constants.Theme.Help.Render(constants.BackHelp()))
+	return constants.DocStyle.Render(b.String())
+}
+
+func (m Model) chainView() string {
+	var b strings.Builder
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		if i == CURVE_INDEX {
+			b.WriteString("\n")
+			for j := 0; j <= 2; j++ {
+				b.WriteString("\t (" + strconv.Itoa(j) + ") " + tuiutils.GetCurveName(archethic.Curve(j)) + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	button := constants.Theme.Button("Load", m.focusIndex == LOAD_BUTTON_INDEX)
+	fmt.Fprintf(&b, "\n%s\n\n", button)
+
+	if len(m.pages) == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "> Transactions of %s (page %d):\n", m.chainAddress, len(m.pages))
+	if len(m.transactions) == 0 {
+		b.WriteString("No transaction in this chain\n\n")
+		return b.String()
+	}
+	for i, transaction := range m.transactions {
+		line := fmt.Sprintf("%s  %-16s %s", formatTimestamp(transaction.ValidationStamp.Timestamp), transaction.Type, transaction.Address)
+		b.WriteString(m.rowView(i, line))
+	}
+	b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("press '%s'/'%s' for the next/previous page", constants.Keymap.NextPage.Help().Key, constants.Keymap.PrevPage.Help().Key)))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+func (m Model) sessionView() string {
+	var b strings.Builder
+	b.WriteString("> Transactions sent during this session:\n")
+	if len(m.sessionTransactions) == 0 {
+		b.WriteString("No transaction sent yet\n\n")
+		return b.String()
+	}
+	for i, transaction := range m.sessionTransactions {
+		status := transaction.Status
+		if transaction.MaxConfirmations > 0 {
+			status = fmt.Sprintf("%s (%d/%d)", status, transaction.Confirmations, transaction.MaxConfirmations)
+		}
+		line := fmt.Sprintf("%s  %-16s %-20s %s", transaction.SentAt.Format(time.TimeOnly), transaction.Type, status, transaction.Address)
+		if transaction.Error != "" {
+			line += "\n    " + constants.ErrStyle(transaction.Error)
+		}
+		b.WriteString(m.rowView(i, line))
+	}
+	b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("press '%s' to display the details of the selected transaction", constants.Keymap.Enter.Help().Key)))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+func (m Model) journalView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "> Transactions sent from this machine (%s):\n", tuiutils.JournalPath())
+	if len(m.journalEntries) == 0 {
+		b.WriteString("No transaction in the journal\n\n")
+		return b.String()
+	}
+	for i, entry := range m.journalEntries {
+		line := fmt.Sprintf("%s  %-16s %-10s %-18s %s", entry.Timestamp.Local().Format(time.DateTime), entry.Type, entry.Status, entry.Command, entry.Address)
+		b.WriteString(m.rowView(i, line))
+	}
+	b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("press '%s' to display the details of the selected entry", constants.Keymap.Enter.Help().Key)))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+func (m Model) rowView(i int, line string) string {
+	return constants.Theme.Row(line, m.listFocused() && i == m.cursor) + "\n"
+}
+
+// Endpoints returns the endpoints the chain is fetched from
+func (m Model) Endpoints() string {
+	return m.inputs[URL_INDEX].Value()
+}
+
+// Editing returns true when the user is typing in a field
+func (m Model) Editing() bool {
+	return m.activeTab == CHAIN_TAB && m.focusIndex < LOAD_BUTTON_INDEX
+}
+
+// KeyMap returns the key bindings displayed in the help overlay
+func (m Model) KeyMap() constants.HelpKeyMap {
+	return constants.HelpKeyMap{
+		{constants.Keymap.NextTab, constants.Keymap.PrevTab, constants.Keymap.Up, constants.Keymap.Down, constants.Keymap.Enter},
+		{constants.Keymap.NextPage, constants.Keymap.PrevPage, constants.Keymap.Copy, constants.Keymap.Refresh, constants.Keymap.ScrollUp, constants.Keymap.ScrollDown, constants.Keymap.Top, constants.Keymap.Bottom},
+		{constants.Keymap.Back, constants.Keymap.Help, constants.Keymap.Quit},
+	}
+}
+
+func transactionView(transaction tuiutils.TransactionGQL) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Address:             %s\n", transaction.Address)
+	fmt.Fprintf(&b, "Type:                %s\n", transaction.Type)
+	fmt.Fprintf(&b, "Version:             %d\n", transaction.Version)
+	fmt.Fprintf(&b, "Validated at:        %s\n", formatTimestamp(transaction.ValidationStamp.Timestamp))
+	fmt.Fprintf(&b, "Previous public key: %s\n", transaction.PreviousPublicKey)
+
+	if len(transaction.Data.Ledger.Uco.Transfers) > 0 {
+		b.WriteString("UCO transfers:\n")
+		for _, transfer := range transaction.Data.Ledger.Uco.Transfers {
+			fmt.Fprintf(&b, "  - %s UCO to %s\n", tuiutils.FormatAmount(transfer.Amount), transfer.To)
+		}
+	}
+	if len(transaction.Data.Ledger.Token.Transfers) > 0 {
+		b.WriteString("Token transfers:\n")
+		for _, transfer := range transaction.Data.Ledger.Token.Transfers {
+			fmt.Fprintf(&b, "  - %s of token %s (id %d) to %s\n", tuiutils.FormatAmount(transfer.Amount), transfer.TokenAddress, transfer.TokenId, transfer.To)
+		}
+	}
+	if len(transaction.Data.Recipients) > 0 {
+		b.WriteString("Recipients:\n")
+		for _, recipient := range transaction.Data.Recipients {
+			fmt.Fprintf(&b, "  - %s\n", recipient)
+		}
+	}
+	if len(transaction.Data.Ownerships) > 0 {
+		b.WriteString("Ownerships:\n")
+		for i, ownership := range transaction.Data.Ownerships {
+			fmt.Fprintf(&b, "  - #%d secret %s\n", i, ownership.Secret)
+			for _, authorizedKey := range ownership.AuthorizedPublicKeys {
+				fmt.Fprintf(&b, "      authorized key %s\n", authorizedKey.PublicKey)
+			}
+		}
+	}
+	if transaction.Data.Content != "" {
+		b.WriteString("Content:\n")
+		b.WriteString(transaction.Data.Content)
+		b.WriteString("\n")
+	}
+	if transaction.Data.Code != "" {
+		b.WriteString("Code:\n")
+		b.WriteString(transaction.Data.Code)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func journalEntryView(entry tuiutils.JournalEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Address:  %s\n", entry.Address)
+	fmt.Fprintf(&b, "Type:     %s\n", entry.Type)
+	fmt.Fprintf(&b, "Sent at:  %s\n", entry.Timestamp.Local().Format(time.DateTime))
+	fmt.Fprintf(&b, "Operator: %s@%s\n", entry.Operator, entry.Host)
+	fmt.Fprintf(&b, "Command:  %s\n", entry.Command)
+	fmt.Fprintf(&b, "Endpoint: %s\n", entry.Endpoint)
+	fmt.Fprintf(&b, "Fee:      %s UCO\n", tuiutils.FormatAmount(entry.Fee))
+	fmt.Fprintf(&b, "Status:   %s\n", entry.Status)
+	if entry.Error != "" {
+		fmt.Fprintf(&b, "Error:    %s\n", entry.Error)
+	}
+	if len(entry.Transfers) > 0 {
+		b.WriteString("Transfers:\n")
+		for _, transfer := range entry.Transfers {
+			if transfer.TokenAddress == "" {
+				fmt.Fprintf(&b, "  - %s UCO to %s\n", tuiutils.FormatAmount(transfer.Amount), transfer.To)
+			} else {
+				fmt.Fprintf(&b, "  - %s of token %s (id %d) to %s\n", tuiutils.FormatAmount(transfer.Amount), transfer.TokenAddress, transfer.TokenId, transfer.To)
+			}
+		}
+	}
+	return b.String()
+}
+
+func formatTimestamp(timestamp int64) string {
+	if timestamp == 0 {
+		return "-"
+	}
+	return time.Unix(timestamp, 0).Format(time.DateTime)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
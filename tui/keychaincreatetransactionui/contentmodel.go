@@ -2,22 +2,46 @@ package keychaincreatetransactionui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-var (
-	focusedPasteContentButton = focusedStyle.Copy().Render("[ Paste ]")
-	blurredPasteContentButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Paste"))
+const (
+	CONTENT_EDITOR_INDEX    = 0
+	CONTENT_LOAD_FILE_INDEX = 1
+	CONTENT_ENCODING_INDEX  = 2
+	CONTENT_PASTE_INDEX     = 3
+
+	// lines of the tab which are not part of the editor: tabs, borders, buttons, sizes and help
+	contentChromeHeight = 24
+	minContentHeight    = 5
+	// bytes of a binary content displayed in place of the editor, and of the encoded payload
+	binaryPreviewSize  = 256
+	encodedPreviewSize = 64
 )
 
 type ContentModel struct {
 	contentTextAreaInput textarea.Model
-	focusInput           int
-	enablePaste          bool
+	filePicker           filepicker.Model
+	picking              bool
+	// content of a binary file, which can't be edited and replaces the text of the editor
+	binaryContent []byte
+	fileName      string
+	encoding      tuiutils.ContentEncoding
+	encoded       []byte
+	focusInput    int
+	enablePaste   bool
+	feedback      string
+	// width of the tab, the buttons being stacked when it is narrow
+	width int
 }
 
 type UpdateContent struct {
@@ -25,12 +49,18 @@ type UpdateContent struct {
 }
 
 func NewContentModel() ContentModel {
-	m := ContentModel{}
+	m := ContentModel{encoding: tuiutils.RawContent}
 	m.contentTextAreaInput = textarea.New()
 	m.contentTextAreaInput.CharLimit = 0
 	m.contentTextAreaInput.MaxHeight = 0
 	m.contentTextAreaInput.SetHeight(20)
 	m.contentTextAreaInput.SetWidth(150)
+	m.filePicker = filepicker.New()
+	m.filePicker.AutoHeight = false
+	m.filePicker.Height = 20
+	if dir, err := os.Getwd(); err == nil {
+		m.filePicker.CurrentDirectory = dir
+	}
 	_, err := clipboard.ReadAll()
 	if err != nil {
 		m.enablePaste = false
@@ -46,87 +76,248 @@ func (m ContentModel) Init() tea.Cmd {
 
 func (m ContentModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
+		if m.picking {
+			if key.Matches(msg, constants.Keymap.Back) {
+				m.picking = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.filePicker, cmd = m.filePicker.Update(msg)
+			if didSelect, path := m.filePicker.DidSelectFile(msg); didSelect {
+				m.picking = false
+				return m, m.loadFile(path)
+			}
+			return m, cmd
+		}
+
+		switch {
 
-		case "esc":
+		case key.Matches(msg, constants.Keymap.Back):
 
 			if m.contentTextAreaInput.Focused() {
 				m.contentTextAreaInput.Blur()
 			}
 			return m, nil
 
-		case "up", "down":
-			if !m.contentTextAreaInput.Focused() && m.enablePaste {
-				updateContentFocusInput(&m, keypress)
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
+			if !m.contentTextAreaInput.Focused() {
+				updateContentFocusInput(&m, key.Matches(msg, constants.Keymap.Up))
 			} else {
 				return updateContentValue(&m, msg)
 			}
 
-		case "enter":
-			// Paste button
-			if m.focusInput == 1 && m.enablePaste {
-				if !m.contentTextAreaInput.Focused() {
+		case key.Matches(msg, constants.Keymap.Enter) && !m.contentTextAreaInput.Focused():
+			switch m.focusInput {
+			case CONTENT_LOAD_FILE_INDEX:
+				m.picking = true
+				m.feedback = ""
+				return m, m.filePicker.Init()
+			case CONTENT_ENCODING_INDEX:
+				m.encoding = nextContentEncoding(m.encoding)
+				return m, m.updateContent()
+			case CONTENT_PASTE_INDEX:
+				if m.binaryContent != nil {
+					return m, nil
+				}
+				m.contentTextAreaInput.Focus()
+				m.focusInput = CONTENT_EDITOR_INDEX
+				return updateContentValue(&m, textarea.Paste())
+			default:
+				if m.binaryContent == nil {
 					m.contentTextAreaInput.Focus()
-					m.focusInput = 0
 				}
+				return m, nil
+			}
 
-				newText := textarea.Paste()
-				return updateContentValue(&m, newText)
-			} else {
-				return updateContentValue(&m, msg)
+		case m.binaryContent != nil && !m.contentTextAreaInput.Focused():
+			// the binary content is replaced by an empty text on delete, the other keys are ignored
+			if m.focusInput == CONTENT_EDITOR_INDEX && key.Matches(msg, constants.Keymap.Delete) {
+				m.binaryContent = nil
+				m.fileName = ""
+				return m, m.updateContent()
 			}
+
 		default:
 
 			if !m.contentTextAreaInput.Focused() {
 				m.contentTextAreaInput.Focus()
-				m.focusInput = 0
+				m.focusInput = CONTENT_EDITOR_INDEX
 			}
 			return updateContentValue(&m, msg)
 		}
+	default:
+		// the file picker reads the directories asynchronously
+		if m.picking {
+			var cmd tea.Cmd
+			m.filePicker, cmd = m.filePicker.Update(msg)
+			return m, cmd
+		}
 	}
 
 	return m, nil
 }
 
-func updateContentValue(m *ContentModel, msg tea.Msg) (ContentModel, func() tea.Msg) {
-	m.contentTextAreaInput, _ = m.contentTextAreaInput.Update(msg)
-	return *m, func() tea.Msg {
-		return UpdateContent{Content: []byte(m.contentTextAreaInput.Value())}
+// setSize fits the editor and the file picker in the window
+func (m *ContentModel) setSize(width int, height int) {
+	m.width = tabWidth(width)
+	m.contentTextAreaInput.SetWidth(m.width)
+	editorHeight := height - contentChromeHeight
+	if editorHeight < minContentHeight {
+		editorHeight = minContentHeight
+	}
+	m.contentTextAreaInput.SetHeight(editorHeight)
+	m.filePicker.Height = editorHeight
+}
+
+// SetContent replaces the content, which is encoded with the given encoding, and returns the encoded content
+func (m *ContentModel) SetContent(content []byte, encoding tuiutils.ContentEncoding) ([]byte, error) {
+	encoded, err := tuiutils.EncodeContent(content, encoding)
+	if err != nil {
+		return nil, err
+	}
+	m.encoding = encoding
+	m.encoded = encoded
+	m.fileName = ""
+	if tuiutils.IsBinaryContent(content) {
+		m.binaryContent = content
+		m.contentTextAreaInput.Reset()
+	} else {
+		m.binaryContent = nil
+		m.contentTextAreaInput.SetValue(string(content))
 	}
+	return encoded, nil
 }
 
-func updateContentFocusInput(m *ContentModel, keypress string) {
-	if keypress == "up" {
+// loadFile replaces the content by the content of the file: in the editor for a text file, as is for a binary file
+func (m *ContentModel) loadFile(path string) tea.Cmd {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		m.feedback = err.Error()
+		return nil
+	}
+	m.contentTextAreaInput.Blur()
+	m.focusInput = CONTENT_EDITOR_INDEX
+	if tuiutils.IsBinaryContent(content) {
+		m.binaryContent = content
+		m.contentTextAreaInput.Reset()
+	} else {
+		m.binaryContent = nil
+		m.contentTextAreaInput.SetValue(string(content))
+	}
+	m.fileName = path
+	m.feedback = fmt.Sprintf("Content loaded from %s (%s)", path, tuiutils.FormatSize(len(content)))
+	return m.updateContent()
+}
+
+// source returns the content before its encoding
+func (m ContentModel) source() []byte {
+	if m.binaryContent != nil {
+		return m.binaryContent
+	}
+	return []byte(m.contentTextAreaInput.Value())
+}
+
+// updateContent encodes the content and sends it to the transaction
+func (m *ContentModel) updateContent() tea.Cmd {
+	content, err := tuiutils.EncodeContent(m.source(), m.encoding)
+	if err != nil {
+		m.feedback = err.Error()
+		return nil
+	}
+	m.encoded = content
+	return func() tea.Msg {
+		return UpdateContent{Content: content}
+	}
+}
+
+func updateContentValue(m *ContentModel, msg tea.Msg) (ContentModel, tea.Cmd) {
+	m.contentTextAreaInput, _ = m.contentTextAreaInput.Update(msg)
+	cmd := m.updateContent()
+	return *m, cmd
+}
+
+func updateContentFocusInput(m *ContentModel, up bool) {
+	last := CONTENT_ENCODING_INDEX
+	if m.enablePaste {
+		last = CONTENT_PASTE_INDEX
+	}
+	if up {
 		m.focusInput--
 	} else {
 		m.focusInput++
 	}
-	if m.focusInput > 1 {
+	if m.focusInput > last {
 		m.focusInput = 0
 	} else if m.focusInput < 0 {
-		m.focusInput = 1
+		m.focusInput = last
 	}
 }
 
+// nextContentEncoding returns the encoding following the given one, cycling through the encodings
+func nextContentEncoding(encoding tuiutils.ContentEncoding) tuiutils.ContentEncoding {
+	for i, e := range tuiutils.ContentEncodings {
+		if e == encoding {
+			return tuiutils.ContentEncodings[(i+1)%len(tuiutils.ContentEncodings)]
+		}
+	}
+	return tuiutils.RawContent
+}
+
 func (m *ContentModel) SwitchTab() (ContentModel, []tea.Cmd) {
 	return *m, nil
 }
 
+// Editing returns true when the user is typing in the editor or browsing the files
+func (m ContentModel) Editing() bool {
+	return m.contentTextAreaInput.Focused() || m.picking
+}
+
 func (m ContentModel) View() string {
 	var b strings.Builder
 
-	b.WriteString(m.contentTextAreaInput.View())
+	if m.picking {
+		b.WriteString("Select the file of the content:\n\n")
+		b.WriteString(m.filePicker.View())
+		b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("\npress '%s' to cancel ", constants.Keymap.Back.Help().Key)))
+		return b.String()
+	}
+
+	if m.binaryContent != nil {
+		fmt.Fprintf(&b, "Binary content of %s:\n\n%s\n", m.fileName, tuiutils.HexPreview(m.binaryContent, binaryPreviewSize))
+		if m.focusInput == CONTENT_EDITOR_INDEX {
+			b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("press '%s' to replace it by a text ", constants.Keymap.Delete.Help().Key)))
+		}
+	} else {
+		b.WriteString(m.contentTextAreaInput.View())
+	}
 
 	if m.contentTextAreaInput.Focused() {
-		b.WriteString(helpStyle.Render("\npress 'esc' to exit edit mode "))
+		b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("\npress '%s' to exit edit mode ", constants.Keymap.Back.Help().Key)))
 	}
-	button := &blurredPasteContentButton
-	if m.focusInput == 1 {
-		button = &focusedPasteContentButton
+
+	source := m.source()
+	fmt.Fprintf(&b, "\n\nSize: %s", tuiutils.FormatSize(len(source)))
+	if m.encoding != tuiutils.RawContent {
+		fmt.Fprintf(&b, ", %s encoded: %s", m.encoding, tuiutils.FormatSize(len(m.encoded)))
+		if tuiutils.IsBinaryContent(m.encoded) && len(source) > 0 {
+			fmt.Fprintf(&b, "\n%s", constants.Theme.Blurred.Render(tuiutils.HexPreview(m.encoded, encodedPreviewSize)))
+		}
 	}
+
+	loadFileButton := constants.Theme.Button("Load file", m.focusInput == CONTENT_LOAD_FILE_INDEX)
+	encodingButton := constants.Theme.Button("Encoding: "+string(m.encoding), m.focusInput == CONTENT_ENCODING_INDEX)
+	pasteButton := constants.Theme.Button("Paste", m.focusInput == CONTENT_PASTE_INDEX)
+	buttons := []string{loadFileButton, encodingButton}
 	if m.enablePaste {
-		fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+		buttons = append(buttons, pasteButton)
+	}
+	fmt.Fprintf(&b, "\n\n%s", constants.Columns(m.width, buttons...))
+	b.WriteString("\n\n")
+	if m.feedback != "" {
+		fmt.Fprintf(&b, "%s\n\n", m.feedback)
 	}
 	return b.String()
 }
@@ -5,10 +5,13 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 var (
@@ -51,6 +54,8 @@ type MainModel struct {
 	focusInput              int
 	feedback                string
 	pvKeyBytes              []byte
+	// width of the tab, the buttons being stacked when it is narrow
+	width int
 }
 
 type UpdateTransactionIndex struct {
@@ -80,20 +85,29 @@ type GetTransactionFee struct {
 
 type ResetInterface struct{}
 
+type LoadTemplate struct {
+	Path string
+}
+
+type SaveTemplate struct {
+	Path  string
+	Curve archethic.Curve
+}
+
 func NewMainModel(pvKeyBytes []byte) MainModel {
 
 	m := MainModel{
-		mainInputs: make([]textinput.Model, 5),
+		mainInputs: make([]textinput.Model, 6),
 		pvKeyBytes: pvKeyBytes,
 	}
 
 	for i := range m.mainInputs {
 		t := textinput.New()
-		t.CursorStyle = cursorStyle
+		t.CursorStyle = constants.Theme.Cursor
 		switch i {
-		case 0:
+		case URL_INPUT:
 			t.Prompt = ""
-		case 1:
+		case SEED_INPUT:
 			t.Prompt = "> Access seed\n"
 			if pvKeyBytes != nil {
 				t.Placeholder = "(Imported SSH key)"
@@ -101,17 +115,20 @@ func NewMainModel(pvKeyBytes []byte) MainModel {
 				t.EchoMode = textinput.EchoPassword
 				t.EchoCharacter = '•'
 			}
-		case 2:
+		case CURVE_INPUT:
 			t.Prompt = "> Elliptic curve\n"
 			t.Placeholder = "(default 0)"
 			t.CharLimit = 1
 			t.Validate = curveValidator
-		case 3:
+		case INDEX_INPUT:
 			t.Prompt = "> Index\n"
 			t.Placeholder = "(default 0)"
 			t.Validate = numberValidator
 		case 4:
 			t.Prompt = ""
+		case TEMPLATE_PATH_INPUT:
+			t.Prompt = "> Template file\n"
+			t.Placeholder = "transaction.yaml"
 		}
 		m.mainInputs[i] = t
 	}
@@ -126,19 +143,19 @@ func (m MainModel) Init() tea.Cmd {
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case CreateTransactionMsg:
-		m.mainInputs[1].SetValue(msg.Seed)
-		m.mainInputs[0].SetValue(msg.Url)
+		m.mainInputs[SEED_INPUT].SetValue(msg.Seed)
+		m.mainInputs[URL_INPUT].SetValue(msg.Url)
 		m.serviceName = msg.ServiceName
 		m.serviceMode = m.serviceName != ""
 		if m.serviceMode {
 			m.focusInput = FIRST_TRANSACTION_TYPE_INDEX
 		}
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
+		switch {
 
-		case "up", "down":
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
 			previousFocus := m.focusInput
-			updateMainFocusInput(&m, keypress)
+			updateMainFocusInput(&m, key.Matches(msg, constants.Keymap.Up))
 			// if the seed or the curve are blured, they are probably updated, so update the transaction index
 			if previousFocus == SEED_INDEX || previousFocus == CURVE_INDEX {
 				var seed []byte
@@ -146,30 +163,30 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					seed = m.pvKeyBytes
 				} else {
 					var err error
-					seed, err = archethic.MaybeConvertToHex(m.mainInputs[1].Value())
+					seed, err = tuiutils.DecodeSecret(m.mainInputs[SEED_INPUT].Value())
 					if err != nil {
 						m.feedback = err.Error()
 						return m, nil
 					}
 				}
 
-				index, err := tuiutils.GetLastTransactionIndex(m.mainInputs[0].Value(), getCurve(&m), seed)
+				index, err := tuiutils.GetLastTransactionIndex(m.mainInputs[URL_INPUT].Value(), getCurve(&m), seed)
 				if err != nil {
 					m.feedback = err.Error()
 					return m, nil
 				}
-				m.mainInputs[3].SetValue(fmt.Sprint(index))
+				m.mainInputs[INDEX_INPUT].SetValue(fmt.Sprint(index))
 				m, cmds := updateMainFocus(m)
 				cmds = append(cmds, m.updateMainInputs(msg)...)
 				return m, func() tea.Msg {
 					return UpdateTransactionIndex{Index: index, cmds: cmds}
 				}
 			}
-		case "enter":
+		case key.Matches(msg, constants.Keymap.Enter):
 
 			if m.focusInput < URL_INDEX {
 				u := urlType[m.focusInput]
-				m.mainInputs[0].SetValue(urls[u])
+				m.mainInputs[URL_INPUT].SetValue(urls[u])
 				m.selectedUrl = u
 				m.focusInput = URL_INDEX
 				m, cmds := updateMainFocus(m)
@@ -187,16 +204,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			} else if m.focusInput == MAIN_ADD_BUTTON_INDEX {
 				return m, func() tea.Msg {
-					var seed []byte
-					if m.pvKeyBytes != nil {
-						seed = m.pvKeyBytes
-					} else {
-						var err error
-						seed, err = archethic.MaybeConvertToHex(m.mainInputs[1].Value())
-						if err != nil {
-							m.feedback = err.Error()
-							return m
-						}
+					seed, err := m.chainSeed()
+					if err != nil {
+						m.feedback = err.Error()
+						return m
 					}
 					return SendTransaction{Curve: getCurve(&m), Seed: seed}
 				}
@@ -204,18 +215,20 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg {
 					return ResetInterface{}
 				}
+			} else if m.focusInput == MAIN_LOAD_TEMPLATE_BUTTON_INDEX {
+				return m, func() tea.Msg {
+					return LoadTemplate{Path: m.templatePath()}
+				}
+			} else if m.focusInput == MAIN_SAVE_TEMPLATE_BUTTON_INDEX {
+				return m, func() tea.Msg {
+					return SaveTemplate{Path: m.templatePath(), Curve: getCurve(&m)}
+				}
 			} else if m.focusInput == MAIN_GET_TRANSACTION_FEE_BUTTON_INDEX {
 				return m, func() tea.Msg {
-					var seed []byte
-					if m.pvKeyBytes != nil {
-						seed = m.pvKeyBytes
-					} else {
-						var err error
-						seed, err = archethic.MaybeConvertToHex(m.mainInputs[1].Value())
-						if err != nil {
-							m.feedback = err.Error()
-							return m
-						}
+					seed, err := m.chainSeed()
+					if err != nil {
+						m.feedback = err.Error()
+						return m
 					}
 					return GetTransactionFee{Curve: getCurve(&m), Seed: seed}
 				}
@@ -224,7 +237,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		default:
 			if m.focusInput == URL_INDEX {
 				return m, func() tea.Msg {
-					return UpdateUrl{Url: m.mainInputs[0].Value()}
+					return UpdateUrl{Url: m.mainInputs[URL_INPUT].Value()}
 				}
 			}
 		}
@@ -236,31 +249,55 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func getCurve(m *MainModel) archethic.Curve {
-	curveInt, err := strconv.Atoi(m.mainInputs[2].Value())
+	curveInt, err := strconv.Atoi(m.mainInputs[CURVE_INPUT].Value())
 	if err != nil {
 		curveInt = 0
 	}
 	return archethic.Curve(curveInt)
 }
 
-func updateMainFocusInput(m *MainModel, keypress string) {
-	if keypress == "up" {
+// setUrl selects the predefined node endpoint matching the url, or the custom one
+func (m *MainModel) setUrl(url string) {
+	m.mainInputs[URL_INPUT].SetValue(url)
+	m.selectedUrl = ""
+	if url == "" {
+		return
+	}
+	for _, u := range urlType {
+		if urls[u] == url {
+			m.selectedUrl = u
+			return
+		}
+	}
+	m.selectedUrl = "Custom"
+}
+
+// templatePath returns the path of the template file, transaction.yaml by default
+func (m MainModel) templatePath() string {
+	if m.mainInputs[TEMPLATE_PATH_INPUT].Value() == "" {
+		return m.mainInputs[TEMPLATE_PATH_INPUT].Placeholder
+	}
+	return m.mainInputs[TEMPLATE_PATH_INPUT].Value()
+}
+
+func updateMainFocusInput(m *MainModel, up bool) {
+	if up {
 		m.focusInput--
 	} else {
 		m.focusInput++
 	}
 
 	if m.serviceMode {
-		if m.focusInput > MAIN_RESET_BUTTON_INDEX {
+		if m.focusInput > MAIN_SAVE_TEMPLATE_BUTTON_INDEX {
 			m.focusInput = FIRST_TRANSACTION_TYPE_INDEX
 		} else if m.focusInput < FIRST_TRANSACTION_TYPE_INDEX {
-			m.focusInput = MAIN_RESET_BUTTON_INDEX
+			m.focusInput = MAIN_SAVE_TEMPLATE_BUTTON_INDEX
 		}
 	} else {
-		if m.focusInput > MAIN_RESET_BUTTON_INDEX {
+		if m.focusInput > MAIN_SAVE_TEMPLATE_BUTTON_INDEX {
 			m.focusInput = 0
 		} else if m.focusInput < 0 {
-			m.focusInput = MAIN_RESET_BUTTON_INDEX
+			m.focusInput = MAIN_SAVE_TEMPLATE_BUTTON_INDEX
 		}
 	}
 }
@@ -281,15 +318,15 @@ func updateMainFocus(m MainModel) (MainModel, []tea.Cmd) {
 	for i := 0; i <= len(m.mainInputs)-1; i++ {
 		// the first 4 inputs are not focusable fields (node endpoints for URL)
 
-		if i == m.focusInput-len(urlType) {
+		if (i == m.focusInput-len(urlType) && i < TEMPLATE_PATH_INPUT) || (i == TEMPLATE_PATH_INPUT && m.focusInput == MAIN_TEMPLATE_PATH_INDEX) {
 			// Set focused state
 			cmds[i] = m.mainInputs[i].Focus()
 			continue
 		}
 		// Remove focused state
 		m.mainInputs[i].Blur()
-		m.mainInputs[i].PromptStyle = noStyle
-		m.mainInputs[i].TextStyle = noStyle
+		m.mainInputs[i].PromptStyle = lipgloss.NewStyle()
+		m.mainInputs[i].TextStyle = lipgloss.NewStyle()
 	}
 
 	return m, cmds
@@ -310,16 +347,16 @@ func (m MainModel) View() string {
 		b.WriteString("> Node endpoint:\n")
 		b.WriteString(urlView(m))
 		// url field
-		b.WriteString(m.mainInputs[0].View() + "\n\n")
+		b.WriteString(m.mainInputs[URL_INPUT].View() + "\n\n")
 		// seed field
-		b.WriteString(m.mainInputs[1].View() + "\n\n")
+		b.WriteString(m.mainInputs[SEED_INPUT].View() + "\n\n")
 		// curve field
-		b.WriteString(m.mainInputs[2].View() + "\n\n")
+		b.WriteString(m.mainInputs[CURVE_INPUT].View() + "\n\n")
 		for j := 0; j <= 2; j++ {
 			b.WriteString("\t (" + strconv.Itoa(j) + ") " + tuiutils.GetCurveName(archethic.Curve(j)) + "\n")
 		}
 		// index field
-		b.WriteString(m.mainInputs[3].View() + "\n\n")
+		b.WriteString(m.mainInputs[INDEX_INPUT].View() + "\n\n")
 	}
 
 	// transaction type field
@@ -327,30 +364,27 @@ func (m MainModel) View() string {
 	b.WriteString(transactionTypeView(m))
 
 	// send transaction button
-	button := &blurredButton
-	if m.focusInput == MAIN_ADD_BUTTON_INDEX {
-		button = &focusedButton
-	}
+	button := constants.Theme.Button("Add", m.focusInput == MAIN_ADD_BUTTON_INDEX)
 
 	// get transaction fee button
-	getTransactionFeeButton := &blurredGetTransactionFeeButton
-	if m.focusInput == MAIN_GET_TRANSACTION_FEE_BUTTON_INDEX {
-		getTransactionFeeButton = &focusedGetTransactionFeeButton
-	}
+	getTransactionFeeButton := constants.Theme.Button("Get Transaction Fee", m.focusInput == MAIN_GET_TRANSACTION_FEE_BUTTON_INDEX)
 
 	if m.feedback != "" {
 		b.WriteString(m.feedback + "\n\n")
 	}
 
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
-	fmt.Fprintf(&b, "%s\n\n", *getTransactionFeeButton)
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
+	fmt.Fprintf(&b, "%s\n\n", getTransactionFeeButton)
 
 	// reset button
-	resetButton := &blurredResetButton
-	if m.focusInput == MAIN_RESET_BUTTON_INDEX {
-		resetButton = &focusedResetButton
-	}
-	fmt.Fprintf(&b, "%s\n\n", *resetButton)
+	resetButton := constants.Theme.Button("Reset", m.focusInput == MAIN_RESET_BUTTON_INDEX)
+	fmt.Fprintf(&b, "%s\n\n", resetButton)
+
+	// template field and buttons
+	b.WriteString(m.mainInputs[TEMPLATE_PATH_INPUT].View() + "\n\n")
+	loadTemplateButton := constants.Theme.Button("Load template", m.focusInput == MAIN_LOAD_TEMPLATE_BUTTON_INDEX)
+	saveTemplateButton := constants.Theme.Button("Save as template", m.focusInput == MAIN_SAVE_TEMPLATE_BUTTON_INDEX)
+	fmt.Fprintf(&b, "%s\n\n", constants.Columns(m.width, loadTemplateButton, saveTemplateButton))
 
 	return b.String()
 }
@@ -370,11 +404,7 @@ func urlView(m MainModel) string {
 		}
 		u += urlType[i]
 
-		if i == m.focusInput {
-			s.WriteString(focusedStyle.Render(u))
-		} else {
-			s.WriteString(u)
-		}
+		s.WriteString(constants.Theme.Row(u, i == m.focusInput))
 		s.WriteString("\n")
 	}
 
@@ -393,13 +423,17 @@ func transactionTypeView(m MainModel) string {
 			u = "( ) "
 		}
 		u += t
-		if m.focusInput == i+FIRST_TRANSACTION_TYPE_INDEX {
-			s.WriteString(focusedStyle.Render(u))
-		} else {
-			s.WriteString(u)
-		}
+		s.WriteString(constants.Theme.Row(u, m.focusInput == i+FIRST_TRANSACTION_TYPE_INDEX))
 		s.WriteString("\n")
 	}
 
 	return s.String()
 }
+
+// chainSeed returns the seed of the chain signing the transaction: the ssh private key, or the typed seed
+func (m MainModel) chainSeed() ([]byte, error) {
+	if m.pvKeyBytes != nil {
+		return m.pvKeyBytes, nil
+	}
+	return tuiutils.DecodeSecret(m.mainInputs[SEED_INPUT].Value())
+}
@@ -4,13 +4,17 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
-	"math"
 	"strconv"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/cli"
+	"github.com/archethic-foundation/archethic-cli/config"
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -35,24 +39,6 @@ type TransactionFeeSent struct {
 	Model Model
 }
 
-var (
-	focusedStyle                   = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	blurredStyle                   = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	cursorStyle                    = focusedStyle.Copy()
-	noStyle                        = lipgloss.NewStyle()
-	helpStyle                      = blurredStyle.Copy()
-	focusedAddAuthKey              = focusedStyle.Copy().Render("[ Add authorization key ]")
-	blurredAddAuthKey              = fmt.Sprintf("[ %s ]", blurredStyle.Render("Add authorization key"))
-	focusedLoadStorageNouncePK     = focusedStyle.Copy().Render("[ Load Storage Nounce Public Key ]")
-	blurredLoadStorageNouncePK     = fmt.Sprintf("[ %s ]", blurredStyle.Render("Load Storage Nounce Public Key"))
-	focusedButton                  = focusedStyle.Copy().Render("[ Add ]")
-	blurredButton                  = fmt.Sprintf("[ %s ]", blurredStyle.Render("Add"))
-	focusedResetButton             = focusedStyle.Copy().Render("[ Reset ]")
-	blurredResetButton             = fmt.Sprintf("[ %s ]", blurredStyle.Render("Reset"))
-	focusedGetTransactionFeeButton = focusedStyle.Copy().Render("[ Get Transaction Fee ]")
-	blurredGetTransactionFeeButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Get Transaction Fee"))
-)
-
 type RenderFunc func(m Model) string
 type createTransactionTab int
 
@@ -70,6 +56,9 @@ const (
 	MAIN_ADD_BUTTON_INDEX                 = 17
 	MAIN_GET_TRANSACTION_FEE_BUTTON_INDEX = 18
 	MAIN_RESET_BUTTON_INDEX               = 19
+	MAIN_TEMPLATE_PATH_INDEX              = 20
+	MAIN_LOAD_TEMPLATE_BUTTON_INDEX       = 21
+	MAIN_SAVE_TEMPLATE_BUTTON_INDEX       = 22
 	FIRST_TRANSACTION_TYPE_INDEX          = 8
 	URL_INDEX                             = 4
 	SEED_INDEX                            = 5
@@ -77,6 +66,16 @@ const (
 	TRANSACTION_INDEX_FIELD_INDEX         = 7
 )
 
+// indexes of the inputs of the main tab in mainInputs, the indexes above being the ones of the focus.
+// The input 4 is not displayed.
+const (
+	URL_INPUT           = 0
+	SEED_INPUT          = 1
+	CURVE_INPUT         = 2
+	INDEX_INPUT         = 3
+	TEMPLATE_PATH_INPUT = 5
+)
+
 type SwitchTab struct{}
 type Model struct {
 	Tabs                   []string
@@ -89,6 +88,7 @@ type Model struct {
 	contentModel           ContentModel
 	smartContractModel     SmartContractModel
 	transaction            archethic.TransactionBuilder
+	findings               []tuiutils.ValidationFinding
 	secretKey              []byte
 	storageNouncePublicKey string
 	serviceName            string
@@ -97,6 +97,7 @@ type Model struct {
 	url                    string
 	seed                   string
 	transactionIndex       int
+	windowSize             tea.WindowSizeMsg
 	showSpinner            bool
 	Spinner                spinner.Model
 	IsInit                 bool
@@ -106,7 +107,7 @@ type Model struct {
 func New(pvKeyBytes []byte) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = constants.Theme.Spinner
 	key := make([]byte, 32)
 	rand.Read(key)
 	m := Model{
@@ -131,6 +132,9 @@ func (m *Model) resetInterface(pvKeyBytes []byte) {
 	m.ownershipsModel = NewOwnershipsModel(m.secretKey, &m.transaction)
 	m.contentModel = NewContentModel()
 	m.smartContractModel = NewSmartContractModel()
+	if m.windowSize.Width > 0 {
+		m.setSize(m.windowSize.Width, m.windowSize.Height)
+	}
 	if m.serviceMode {
 		w, _ := m.mainModel.Update(CreateTransactionMsg{
 			ServiceName: m.serviceName,
@@ -140,6 +144,20 @@ func (m *Model) resetInterface(pvKeyBytes []byte) {
 		m.mainModel = w.(MainModel)
 		m.ownershipsModel.SetUrl(m.url)
 	}
+	m.validate()
+}
+
+// validate checks the transaction against the rules of the chain, after each change, for the view to display the findings
+func (m *Model) validate() {
+	m.findings = tuiutils.ValidateTransaction(&m.transaction)
+}
+
+// paymentRequestFeedback displays the memo of a payment request pasted in a transfer tab
+func paymentRequestFeedback(request tuiutils.PaymentRequest) string {
+	if request.Memo == "" {
+		return "Payment request loaded"
+	}
+	return "Memo of the payment request: " + request.Memo
 }
 
 func numberValidator(s string) error {
@@ -170,6 +188,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.serviceName = msg.ServiceName
 		m.serviceMode = m.serviceName != ""
 		m.url = msg.Url
+		if seed, err := tuiutils.DecodeSecret(msg.Seed); err == nil && m.serviceMode {
+			tuiutils.SetLoadedChain(tuiutils.LoadedChain{Endpoint: m.url, Seed: seed, ServiceName: m.serviceName})
+		}
 		w, cmds := m.mainModel.Update(msg)
 		m.mainModel = w.(MainModel)
 		m.ownershipsModel.SetUrl(m.url)
@@ -184,6 +205,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = msg.cmds
 	case UpdateTransactionType:
 		m.transaction.SetType(msg.TransactionType)
+		m.validate()
 	case TransactionSent:
 		m.showSpinner = false
 		if msg.Error != nil {
@@ -212,21 +234,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case ResetInterface:
 		m.resetInterface(m.pvKeyBytes)
+	case LoadTemplate:
+		err := loadTemplate(&m, msg.Path)
+		m.validate()
+		if err != nil {
+			m.feedback = err.Error()
+		} else {
+			m.feedback = "Template loaded from " + msg.Path
+		}
+		return m, nil
+	case SaveTemplate:
+		err := saveTemplate(&m, msg.Path, msg.Curve)
+		if err != nil {
+			m.feedback = err.Error()
+		} else {
+			m.feedback = "Template saved to " + msg.Path
+		}
+		return m, nil
 	case AddUcoTransfer:
 		m.transaction.AddUcoTransfer(msg.To, msg.Amount)
 		m.ucoTransferModel.transaction = &m.transaction
+		m.validate()
 		cmds = msg.cmds
 	case AddTokenTransfer:
 		m.transaction.AddTokenTransfer(msg.To, msg.TokenAddress, msg.Amount, msg.TokenId)
 		m.tokenTransferModel.transaction = &m.transaction
+		m.validate()
 		cmds = msg.cmds
 	case AddRecipient:
 		m.transaction.AddRecipient(msg.Recipient)
 		m.recipientsModel.transaction = &m.transaction
+		m.validate()
 		cmds = msg.cmds
 	case AddOwnership:
 		m.transaction.AddOwnership(msg.Cipher, msg.AuthorizedKeys)
 		m.ownershipsModel.transaction = &m.transaction
+		m.validate()
 		cmds = msg.cmds
 	case UpdateStorageNouncePublicKey:
 		m.storageNouncePublicKey = msg.StorageNouncePublicKey
@@ -234,47 +277,80 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		w, _ := m.ownershipsModel.Update(msg)
 		m.ownershipsModel = w.(OwnershipsModel)
 		return m, nil
+	case LoadAuthorizedKeyCandidates:
+		// the services are listed when the seed is the access seed of a keychain
+		seed, _ := m.mainModel.chainSeed()
+		return m, func() tea.Msg {
+			return loadAuthorizedKeyCandidates(m.url, seed)
+		}
+	case AuthorizedKeyCandidatesLoaded:
+		w, _ := m.ownershipsModel.Update(msg)
+		m.ownershipsModel = w.(OwnershipsModel)
+		return m, nil
+	case LoadSecret:
+		seed, err := m.mainModel.chainSeed()
+		curve := getCurve(&m.mainModel)
+		return m, func() tea.Msg {
+			return loadSecrets(m.url, seed, err, curve, m.serviceName, msg.Address)
+		}
+	case SecretsLoaded:
+		w, _ := m.ownershipsModel.Update(msg)
+		m.ownershipsModel = w.(OwnershipsModel)
+		return m, nil
 	case DeleteUcoTransfer:
 		m.transaction.Data.Ledger.Uco.Transfers = append(m.transaction.Data.Ledger.Uco.Transfers[:msg.IndexToDelete], m.transaction.Data.Ledger.Uco.Transfers[msg.IndexToDelete+1:]...)
 		m.ucoTransferModel.transaction = &m.transaction
+		m.validate()
 		return m, nil
 	case DeleteTokenTransfer:
 		m.transaction.Data.Ledger.Token.Transfers = append(m.transaction.Data.Ledger.Token.Transfers[:msg.IndexToDelete], m.transaction.Data.Ledger.Token.Transfers[msg.IndexToDelete+1:]...)
 		m.tokenTransferModel.transaction = &m.transaction
+		m.validate()
 		return m, nil
 	case DeleteRecipient:
 		m.transaction.Data.Recipients = append(m.transaction.Data.Recipients[:msg.IndexToDelete], m.transaction.Data.Recipients[msg.IndexToDelete+1:]...)
 		m.recipientsModel.transaction = &m.transaction
+		m.validate()
 		return m, nil
 	case DeleteOwnership:
 		m.transaction.Data.Ownerships = append(m.transaction.Data.Ownerships[:msg.IndexToDelete], m.transaction.Data.Ownerships[msg.IndexToDelete+1:]...)
 		m.ownershipsModel.transaction = &m.transaction
+		m.validate()
 		return m, nil
 	case UpdateContent:
 		m.transaction.SetContent(msg.Content)
+		m.validate()
 	case UpdateSmartContract:
 		m.transaction.SetCode(msg.Code)
+		m.validate()
+	case tea.WindowSizeMsg:
+		m.windowSize = msg
+		m.setSize(msg.Width, msg.Height)
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "esc":
+		switch {
+		case key.Matches(msg, constants.Keymap.Back):
 			if m.activeTab == SMART_CONTRACT_TAB && m.smartContractModel.smartContractTextAreaInput.Focused() {
 				w, cmds := m.smartContractModel.Update(msg)
 				m.smartContractModel = w.(SmartContractModel)
 				return m, cmds
-			} else if m.activeTab == CONTENT_TAB && m.contentModel.contentTextAreaInput.Focused() {
+			} else if m.activeTab == CONTENT_TAB && m.contentModel.Editing() {
 				w, cmds := m.contentModel.Update(msg)
 				m.contentModel = w.(ContentModel)
 				return m, cmds
+			} else if m.activeTab == OWNERSHIPS_TAB && m.ownershipsModel.picking {
+				w, cmds := m.ownershipsModel.Update(msg)
+				m.ownershipsModel = w.(OwnershipsModel)
+				return m, cmds
 			} else {
 				return New(m.pvKeyBytes), func() tea.Msg {
 					return BackMsg(true)
 				}
 			}
-		case "ctrl+c":
+		case key.Matches(msg, constants.Keymap.Quit):
 			return m, tea.Quit
-		case "right", "tab":
+		case key.Matches(msg, constants.Keymap.NextTab):
 			// switch to the next tab except if the user is editing the content or the smart contract
-			if (m.activeTab == CONTENT_TAB && !m.contentModel.contentTextAreaInput.Focused()) ||
+			if (m.activeTab == CONTENT_TAB && !m.contentModel.Editing()) ||
 				(m.activeTab == SMART_CONTRACT_TAB && !m.smartContractModel.smartContractTextAreaInput.Focused()) ||
 				(m.activeTab != CONTENT_TAB && m.activeTab != SMART_CONTRACT_TAB) {
 				m.activeTab = getNewTab(&m, int(m.activeTab)+1)
@@ -289,9 +365,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, cmds
 			}
 
-		case "left", "shift+tab":
+		case key.Matches(msg, constants.Keymap.PrevTab):
 			// switch to the previous tab except if the user is editing the content or the smart contract
-			if (m.activeTab == CONTENT_TAB && !m.contentModel.contentTextAreaInput.Focused()) ||
+			if (m.activeTab == CONTENT_TAB && !m.contentModel.Editing()) ||
 				(m.activeTab == SMART_CONTRACT_TAB && !m.smartContractModel.smartContractTextAreaInput.Focused()) ||
 				(m.activeTab != CONTENT_TAB && m.activeTab != SMART_CONTRACT_TAB) {
 				m.activeTab = getNewTab(&m, int(m.activeTab)-1)
@@ -346,6 +422,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		spinnerOwnership, cmd2 := m.ownershipsModel.Spinner.Update(msg)
 		m.ownershipsModel.Spinner = spinnerOwnership
 		cmds = append(cmds, cmd2)
+		// and the file picker of the content tab
+		w, cmd3 := m.contentModel.Update(msg)
+		m.contentModel = w.(ContentModel)
+		cmds = append(cmds, cmd3)
 		return m, tea.Batch(cmds...)
 	}
 	return m, tea.Batch(cmds...)
@@ -399,14 +479,33 @@ var (
 	inactiveTabBorder = tabBorderWithBottom("┴", "─", "┴")
 	activeTabBorder   = tabBorderWithBottom("┘", " ", "└")
 	docStyle          = lipgloss.NewStyle().Padding(1, 2, 1, 2)
-	highlightColor    = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
-	inactiveTabStyle  = lipgloss.NewStyle().Border(inactiveTabBorder, true).BorderForeground(highlightColor).Padding(0, 5)
-	activeTabStyle    = inactiveTabStyle.Copy().Border(activeTabBorder, true)
-	windowStyle       = lipgloss.NewStyle().BorderForeground(highlightColor).Padding(2, 0).Align(lipgloss.Left).Border(lipgloss.NormalBorder()).UnsetBorderTop()
 )
 
+// windowStyle is the style of the window displaying the active tab
+func windowStyle() lipgloss.Style {
+	return lipgloss.NewStyle().BorderForeground(constants.Theme.Border).Padding(2, 0).Align(lipgloss.Left).Border(lipgloss.NormalBorder()).UnsetBorderTop()
+}
+
+// tabWidth returns the width available to the content of the tabs in a window of the given width
+func tabWidth(width int) int {
+	h, _ := docStyle.GetFrameSize()
+	return width - h - windowStyle().GetHorizontalFrameSize()
+}
+
+// setSize lays the tabs out in the window
+func (m *Model) setSize(width int, height int) {
+	m.mainModel.width = tabWidth(width)
+	m.ownershipsModel.width = tabWidth(width)
+	m.contentModel.setSize(width, height)
+	m.smartContractModel.setSize(width, height)
+}
+
 func (m Model) View() string {
 	doc := strings.Builder{}
+	// the active tab is open on the window below it, and its title is bold
+	inactiveTabStyle := lipgloss.NewStyle().Border(inactiveTabBorder, true).BorderForeground(constants.Theme.Border).Padding(0, 5)
+	activeTabStyle := inactiveTabStyle.Copy().Border(activeTabBorder, true).Bold(true)
+	window := windowStyle()
 
 	var renderedTabs []string
 
@@ -433,6 +532,22 @@ func (m Model) View() string {
 	}
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top, renderedTabs...)
+	windowWidth := lipgloss.Width(row)
+	if m.windowSize.Width > 0 && (constants.Narrow(m.windowSize.Width) || windowWidth > tabWidth(m.windowSize.Width)+window.GetHorizontalFrameSize()) {
+		// the tabs don't fit on a line: they are listed above a closed window as wide as the terminal.
+		// The active tab is not marked as focused, the focus being in the window.
+		var tabs strings.Builder
+		for i, t := range m.Tabs {
+			if i == int(m.activeTab) {
+				tabs.WriteString(constants.Theme.Focused.Render("• "+t) + "\n")
+			} else {
+				tabs.WriteString("  " + t + "\n")
+			}
+		}
+		row = tabs.String()
+		windowWidth = tabWidth(m.windowSize.Width) + window.GetHorizontalFrameSize()
+		window = window.BorderTop(true)
+	}
 	doc.WriteString(row)
 	doc.WriteString("\n")
 
@@ -462,13 +577,107 @@ func (m Model) View() string {
 		b.WriteString(m.smartContractModel.View())
 	}
 	b.WriteString("\n\n")
+	b.WriteString(m.validationView())
 	tabContent = b.String()
-	doc.WriteString(windowStyle.Width((lipgloss.Width(row) - windowStyle.GetHorizontalFrameSize())).Render(tabContent))
+	doc.WriteString(window.Width(windowWidth - window.GetHorizontalFrameSize()).Render(tabContent))
 	doc.WriteString("\n\n")
-	doc.WriteString(helpStyle.Render("press 'esc' to go back "))
+	doc.WriteString(constants.Theme.Help.Render(constants.BackHelp()))
 	return docStyle.Render(doc.String())
 }
 
+// tabSections gives the section of the validation findings displayed in each tab
+var tabSections = map[createTransactionTab]string{
+	MAIN_TAB:           "transaction_type",
+	UCO_TAB:            "uco_transfers",
+	TOKEN_TAB:          "token_transfers",
+	RECIPIENTS_TAB:     "recipients",
+	OWNERSHIPS_TAB:     "ownerships",
+	CONTENT_TAB:        "content",
+	SMART_CONTRACT_TAB: "smart_contract",
+}
+
+// validationView renders the findings of the validation of the transaction concerning the active tab,
+// and on the main tab, the number of findings of the other tabs
+func (m Model) validationView() string {
+	var b strings.Builder
+	otherTabs := map[createTransactionTab]int{}
+	for _, finding := range m.findings {
+		tab := MAIN_TAB
+		for t, section := range tabSections {
+			if section == finding.Section() {
+				tab = t
+			}
+		}
+		if tab != m.activeTab {
+			otherTabs[tab]++
+			continue
+		}
+		// the issues of the code's structure are already displayed by the editor
+		if tab == SMART_CONTRACT_TAB && finding.Line != 0 {
+			continue
+		}
+		message := fmt.Sprintf("%s: %s", finding.Field, finding.Message)
+		if finding.Severity == tuiutils.SeverityError {
+			b.WriteString(constants.ErrStyle(message))
+		} else {
+			b.WriteString(constants.AlertStyle(message))
+		}
+		b.WriteString("\n")
+	}
+	if m.activeTab == MAIN_TAB && len(otherTabs) > 0 {
+		var tabs []string
+		for i, name := range m.Tabs {
+			if count := otherTabs[createTransactionTab(i)]; count > 0 {
+				tabs = append(tabs, fmt.Sprintf("%s (%d)", name, count))
+			}
+		}
+		b.WriteString(constants.AlertStyle("Findings in the other tabs: " + strings.Join(tabs, ", ")))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Endpoints returns the endpoints the transaction is sent to
+func (m Model) Endpoints() string {
+	return m.url
+}
+
+// Editing returns true when the user is typing in a field of the active tab
+func (m Model) Editing() bool {
+	var inputs []textinput.Model
+	switch m.activeTab {
+	case MAIN_TAB:
+		inputs = m.mainModel.mainInputs
+	case UCO_TAB:
+		inputs = m.ucoTransferModel.ucoInputs
+	case TOKEN_TAB:
+		inputs = m.tokenTransferModel.tokenInputs
+	case RECIPIENTS_TAB:
+		inputs = []textinput.Model{m.recipientsModel.recipientsInput}
+	case OWNERSHIPS_TAB:
+		return m.ownershipsModel.Editing()
+	case CONTENT_TAB:
+		return m.contentModel.Editing()
+	case SMART_CONTRACT_TAB:
+		return m.smartContractModel.Editing()
+	}
+	for _, input := range inputs {
+		if input.Focused() {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyMap returns the key bindings displayed in the help overlay
+func (m Model) KeyMap() constants.HelpKeyMap {
+	return constants.HelpKeyMap{
+		{constants.Keymap.NextTab, constants.Keymap.PrevTab},
+		{constants.Keymap.Up, constants.Keymap.Down, constants.Keymap.Enter, constants.Keymap.Delete},
+		{constants.Keymap.Back, constants.Keymap.Help, constants.Keymap.Quit},
+	}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -485,7 +694,16 @@ func min(a, b int) int {
 
 func sendTransaction(m *Model, curve archethic.Curve, seed []byte) TransactionSent {
 	m.feedback = ""
-	feedback, error := tuiutils.SendTransaction(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, m.transactionIndex, m.serviceName, m.storageNouncePublicKey, seed)
+	if tuiutils.HasValidationErrors(m.findings) {
+		return TransactionSent{Model: *m, Error: errors.New("transaction not sent: fix the errors reported in the tabs")}
+	}
+	setLoadedChain(m, curve, seed)
+	estimatedFee, err := checkProfileMaxFee(m, curve, seed)
+	if err != nil {
+		return TransactionSent{Model: *m, Error: err}
+	}
+	// the index displayed is the node's one: the next free index is reserved when sending
+	feedback, error := tuiutils.SendTransaction(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, tuiutils.NextIndex, m.serviceName, m.storageNouncePublicKey, seed, estimatedFee)
 	m.feedback = fmt.Sprintf("Transaction sent: %s", feedback)
 	if error != nil {
 		return TransactionSent{Model: *m, Error: error}
@@ -493,15 +711,69 @@ func sendTransaction(m *Model, curve archethic.Curve, seed []byte) TransactionSe
 	return TransactionSent{Model: *m, Error: nil}
 }
 
+// checkProfileMaxFee checks the estimated fee of the transaction doesn't exceed the max_fee of the default profile
+// (when greater than 0), as send-transaction does. The fee estimated is returned to be recorded in the journal.
+func checkProfileMaxFee(m *Model, curve archethic.Curve, seed []byte) (*archethic.Fee, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := cfg.Profile("")
+	if err != nil || profile.MaxFee <= 0 {
+		return nil, err
+	}
+	fee, err := tuiutils.GetTransactionFee(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, m.transactionIndex, m.serviceName, m.storageNouncePublicKey, seed)
+	if err != nil {
+		return nil, err
+	}
+	return &fee, cli.CheckMaxFee(fee, profile.MaxFee)
+}
+
 func getTransactionFee(m *Model, curve archethic.Curve, seed []byte) TransactionFeeSent {
 	m.feedback = ""
+	setLoadedChain(m, curve, seed)
 	fee, error := tuiutils.GetTransactionFee(&m.transaction, m.secretKey, curve, m.serviceMode, m.url, m.transactionIndex, m.serviceName, m.storageNouncePublicKey, seed)
-	humanReadableFee := float64(fee.Fee) / math.Pow(10, 8)
-	usdEquivalent := humanReadableFee * float64(fee.Rates.Usd)
-	eurEquivanlent := humanReadableFee * float64(fee.Rates.Eur)
-	m.feedback = fmt.Sprintf("Transaction fee: %f UCO (~ $%f) (~ %f€)", humanReadableFee, usdEquivalent, eurEquivanlent)
 	if error != nil {
 		return TransactionFeeSent{Model: *m, Error: error}
 	}
+	m.feedback = "Transaction fee: " + tuiutils.FormatFee(fee)
 	return TransactionFeeSent{Model: *m, Error: nil}
 }
+
+// loadSecrets decrypts the ownership secrets of the transaction with any key of the chain
+func loadSecrets(url string, seed []byte, seedErr error, curve archethic.Curve, serviceName string, address string) SecretsLoaded {
+	if seedErr != nil {
+		return SecretsLoaded{Error: seedErr}
+	}
+	keypairs, err := tuiutils.CandidateKeypairs(url, seed, curve, serviceName, -1)
+	if err != nil {
+		return SecretsLoaded{Error: err}
+	}
+	secrets, err := tuiutils.ReadSecrets(url, address, keypairs)
+	return SecretsLoaded{Address: address, Secrets: secrets, Error: err}
+}
+
+// setLoadedChain records the chain of the transaction being built so it can be browsed from the history view
+func setLoadedChain(m *Model, curve archethic.Curve, seed []byte) {
+	tuiutils.SetLoadedChain(tuiutils.LoadedChain{Endpoint: m.url, Seed: seed, Curve: curve, ServiceName: m.serviceName})
+}
+
+// loadAuthorizedKeyCandidates lists the public keys of the keychain's services, of the contacts and the storage nonce public key
+func loadAuthorizedKeyCandidates(url string, seed []byte) AuthorizedKeyCandidatesLoaded {
+	cfg, err := config.Load()
+	if err != nil {
+		return AuthorizedKeyCandidatesLoaded{Error: err}
+	}
+	resolver := tuiutils.KeyResolver{Endpoint: url, AccessSeed: seed, Contacts: cfg.Contacts}
+	candidates, err := resolver.Candidates()
+	storageNoncePublicKey, storageNonceErr := tuiutils.GetStorageNoncePublicKey(url)
+	if storageNonceErr == nil {
+		candidates = append(candidates, tuiutils.AuthorizedKeyCandidate{Reference: "storage nonce", PublicKey: strings.ToUpper(storageNoncePublicKey)})
+	} else if err == nil {
+		err = storageNonceErr
+	}
+	if len(candidates) == 0 {
+		return AuthorizedKeyCandidatesLoaded{Error: err}
+	}
+	return AuthorizedKeyCandidatesLoaded{Candidates: candidates}
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,14 +20,30 @@ type OwnershipsModel struct {
 	focusInput             int
 	ownershipsInputs       []textinput.Model
 	authorizedKeys         []string
+	pendingOwnerships      []pendingOwnership
 	url                    string
 	storageNouncePublicKey string
 	secretKey              []byte
 	transaction            *archethic.TransactionBuilder
 	feedback               string
 	showSpinner            bool
-	Spinner                spinner.Model
-	IsInit                 bool
+	// picker of the public keys of the keychain's services, the contacts and the storage nonce
+	picking      bool
+	candidates   []tuiutils.AuthorizedKeyCandidate
+	pickerCursor int
+	picked       map[int]bool
+	Spinner      spinner.Model
+	IsInit       bool
+	// width of the tab, the buttons being stacked when it is narrow
+	width int
+}
+
+// pendingOwnership is an ownership waiting to be added: loaded from a template without its secret,
+// or decrypted from an existing transaction to be shared again
+type pendingOwnership struct {
+	secret         []byte
+	authorizedKeys []string
+	address        string
 }
 
 type AddOwnership struct {
@@ -40,6 +59,26 @@ type SendUpdateStorageNouncePublicKey struct {
 type UpdateStorageNouncePublicKey struct {
 	StorageNouncePublicKey string
 }
+
+// LoadSecret asks for the decryption of the ownership secrets of a transaction with the keys of the chain
+type LoadSecret struct {
+	Address string
+}
+
+// LoadAuthorizedKeyCandidates asks for the public keys which can be picked as authorized keys
+type LoadAuthorizedKeyCandidates struct{}
+
+type AuthorizedKeyCandidatesLoaded struct {
+	Candidates []tuiutils.AuthorizedKeyCandidate
+	Error      error
+}
+
+type SecretsLoaded struct {
+	Address string
+	Secrets []tuiutils.DecryptedSecret
+	Error   error
+}
+
 type DeleteOwnership struct {
 	IndexToDelete int
 }
@@ -48,17 +87,17 @@ func NewOwnershipsModel(secretKey []byte, transaction *archethic.TransactionBuil
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = constants.Theme.Spinner
 
 	m := OwnershipsModel{
-		ownershipsInputs: make([]textinput.Model, 2),
+		ownershipsInputs: make([]textinput.Model, 3),
 		secretKey:        secretKey,
 		transaction:      transaction,
 		Spinner:          s,
 	}
 	for i := range m.ownershipsInputs {
 		t := textinput.New()
-		t.CursorStyle = cursorStyle
+		t.CursorStyle = constants.Theme.Cursor
 		switch i {
 		case 0:
 			t.Prompt = "> Secret:\n"
@@ -66,6 +105,8 @@ func NewOwnershipsModel(secretKey []byte, transaction *archethic.TransactionBuil
 			t.EchoCharacter = '•'
 		case 1:
 			t.Prompt = "> Authorization key:\n"
+		case 2:
+			t.Prompt = "> Share again the secret of the transaction (address):\n"
 		}
 
 		m.ownershipsInputs[i] = t
@@ -80,12 +121,16 @@ func (m OwnershipsModel) Init() tea.Cmd {
 func (m OwnershipsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
+		if m.picking {
+			m.updatePicker(msg)
+			return m, nil
+		}
+		switch {
 
-		case "up", "down":
-			updateOwnershipsFocusInput(&m, keypress)
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
+			updateOwnershipsFocusInput(&m, key.Matches(msg, constants.Keymap.Up))
 
-		case "enter":
+		case key.Matches(msg, constants.Keymap.Enter):
 
 			switch m.focusInput {
 
@@ -96,8 +141,18 @@ func (m OwnershipsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.feedback = fmt.Sprintf("%s", err)
 					return m, nil
 				}
-				// load storage nounce public key
+				// pick the keys of the services, the contacts and the storage nonce
 			case len(m.ownershipsInputs) + len(m.authorizedKeys) + 1:
+				if m.url == "" {
+					m.feedback = "Please select a node endpoint in the main tab"
+					return m, nil
+				}
+				m.showSpinner = true
+				return m, func() tea.Msg {
+					return LoadAuthorizedKeyCandidates{}
+				}
+				// load storage nounce public key
+			case len(m.ownershipsInputs) + len(m.authorizedKeys) + 2:
 				if m.url == "" {
 					m.feedback = "Please select a node endpoint in the main tab"
 					return m, nil
@@ -112,10 +167,25 @@ func (m OwnershipsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, func() tea.Msg {
 					return UpdateStorageNouncePublicKey{StorageNouncePublicKey: m.storageNouncePublicKey}
 				}
+				// load the secrets of an existing transaction
+			case len(m.ownershipsInputs) + len(m.authorizedKeys) + 3:
+				address := m.ownershipsInputs[2].Value()
+				if _, err := hex.DecodeString(address); err != nil || address == "" {
+					m.feedback = "Please type the address of the transaction holding the secret"
+					return m, nil
+				}
+				if m.url == "" {
+					m.feedback = "Please select a node endpoint in the main tab"
+					return m, nil
+				}
+				m.showSpinner = true
+				return m, func() tea.Msg {
+					return LoadSecret{Address: address}
+				}
 				//add ownership
-			case len(m.ownershipsInputs) + len(m.authorizedKeys) + 2:
+			case len(m.ownershipsInputs) + len(m.authorizedKeys) + 4:
 
-				secret, err := archethic.MaybeConvertToHex(m.ownershipsInputs[0].Value())
+				secret, err := tuiutils.DecodeSecret(m.ownershipsInputs[0].Value())
 				if err != nil {
 					m.feedback = fmt.Sprintf("%s", err)
 					return m, nil
@@ -129,32 +199,17 @@ func (m OwnershipsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 
-				cipher, err := archethic.AesEncrypt([]byte(secret), m.secretKey)
+				cipher, authorizedKeys, err := tuiutils.EncryptOwnership(secret, m.authorizedKeys, m.secretKey)
 				if err != nil {
 					m.feedback = fmt.Sprintf("%s", err)
 					return m, nil
 				}
-				authorizedKeys := make([]archethic.AuthorizedKey, len(m.authorizedKeys))
-				for i, key := range m.authorizedKeys {
-					keyByte, err := hex.DecodeString(key)
-					if err != nil {
-						m.feedback = fmt.Sprintf("%s", err)
-						return m, nil
-					}
-					encrypedSecretKey, err := archethic.EcEncrypt(m.secretKey, keyByte)
-					if err != nil {
-						m.feedback = fmt.Sprintf("%s", err)
-						return m, nil
-					}
-					authorizedKeys[i] = archethic.AuthorizedKey{
-						PublicKey:          keyByte,
-						EncryptedSecretKey: encrypedSecretKey,
-					}
-				}
 
 				m.authorizedKeys = []string{}
+				m.feedback = ""
 				m.ownershipsInputs[0].SetValue("")
 				m.ownershipsInputs[1].SetValue("")
+				m.loadNextPendingOwnership()
 				m, cmds := updateOwnershipsFocus(m)
 				cmds = append(cmds, m.updateOwnershipsInputs(msg)...)
 				return m, func() tea.Msg {
@@ -162,15 +217,15 @@ func (m OwnershipsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "d":
+		case key.Matches(msg, constants.Keymap.Delete):
 
 			if m.focusInput > len(m.ownershipsInputs)-1 && m.focusInput < len(m.ownershipsInputs)+len(m.authorizedKeys) {
 				indexToDelete := m.focusInput - len(m.ownershipsInputs)
 				m.focusInput--
 				m.authorizedKeys = append(m.authorizedKeys[:indexToDelete], m.authorizedKeys[indexToDelete+1:]...)
 				return m, nil
-			} else if m.focusInput > len(m.ownershipsInputs)+len(m.authorizedKeys)+2 {
-				indexToDelete := m.focusInput - len(m.ownershipsInputs) - len(m.authorizedKeys) - 3
+			} else if m.focusInput > len(m.ownershipsInputs)+len(m.authorizedKeys)+4 {
+				indexToDelete := m.focusInput - len(m.ownershipsInputs) - len(m.authorizedKeys) - 5
 				m.focusInput--
 				return m, func() tea.Msg {
 					return DeleteOwnership{indexToDelete}
@@ -181,6 +236,35 @@ func (m OwnershipsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case UpdateStorageNouncePublicKey:
 		m.showSpinner = false
 		return m, nil
+	case AuthorizedKeyCandidatesLoaded:
+		m.showSpinner = false
+		if msg.Error != nil {
+			m.feedback = msg.Error.Error()
+			return m, nil
+		}
+		if len(msg.Candidates) == 0 {
+			m.feedback = "No key to pick: no keychain service, contact with a public key or storage nonce public key"
+			return m, nil
+		}
+		m.candidates = msg.Candidates
+		m.pickerCursor = 0
+		m.picked = map[int]bool{}
+		m.picking = true
+		m.feedback = ""
+		return m, nil
+	case SecretsLoaded:
+		m.showSpinner = false
+		if msg.Error != nil {
+			m.feedback = msg.Error.Error()
+			return m, nil
+		}
+		m.ownershipsInputs[2].SetValue("")
+		pendingOwnerships := make([]pendingOwnership, len(msg.Secrets))
+		for i, secret := range msg.Secrets {
+			pendingOwnerships[i] = pendingOwnership{secret: secret.Secret, authorizedKeys: secret.AuthorizedKeys, address: msg.Address}
+		}
+		m.setPendingOwnerships(append(pendingOwnerships, m.pendingOwnerships...))
+		return m, nil
 	default:
 		var cmd tea.Cmd
 		m.Spinner, cmd = m.Spinner.Update(msg)
@@ -193,9 +277,8 @@ func (m OwnershipsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func loadStorageNouncePublicKey(m OwnershipsModel) UpdateStorageNouncePublicKey {
-	client := archethic.NewAPIClient(m.url)
 	var err error
-	m.storageNouncePublicKey, err = client.GetStorageNoncePublicKey()
+	m.storageNouncePublicKey, err = tuiutils.GetStorageNoncePublicKey(m.url)
 	if err != nil {
 		m.feedback = fmt.Sprintf("%s", err)
 	}
@@ -204,12 +287,90 @@ func loadStorageNouncePublicKey(m OwnershipsModel) UpdateStorageNouncePublicKey
 	return UpdateStorageNouncePublicKey{StorageNouncePublicKey: m.storageNouncePublicKey}
 }
 
+// setPendingOwnerships queues the ownerships waiting to be added
+func (m *OwnershipsModel) setPendingOwnerships(pendingOwnerships []pendingOwnership) {
+	m.pendingOwnerships = pendingOwnerships
+	m.loadNextPendingOwnership()
+}
+
+// loadNextPendingOwnership fills the list of authorized keys, and the secret when it is known, with the next pending ownership
+func (m *OwnershipsModel) loadNextPendingOwnership() {
+	if len(m.pendingOwnerships) == 0 {
+		return
+	}
+	ownership := m.pendingOwnerships[0]
+	m.authorizedKeys = ownership.authorizedKeys
+	m.pendingOwnerships = m.pendingOwnerships[1:]
+	if ownership.secret == nil {
+		m.feedback = fmt.Sprintf("Type the secret of the ownership loaded from the template (%d more after this one)", len(m.pendingOwnerships))
+		return
+	}
+	// the secret is hex encoded, so a binary secret is not altered by the input
+	m.ownershipsInputs[0].SetValue(hex.EncodeToString(ownership.secret))
+	m.feedback = fmt.Sprintf("Secret of the transaction %s loaded: add or delete authorized keys, then add the ownership (%d more after this one)", ownership.address, len(m.pendingOwnerships))
+}
+
+// updatePicker moves the cursor of the picker, toggles the selection of its keys, and adds the selected keys on enter
+func (m *OwnershipsModel) updatePicker(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, constants.Keymap.Back):
+		m.picking = false
+	case key.Matches(msg, constants.Keymap.Up):
+		if m.pickerCursor > 0 {
+			m.pickerCursor--
+		}
+	case key.Matches(msg, constants.Keymap.Down):
+		if m.pickerCursor < len(m.candidates)-1 {
+			m.pickerCursor++
+		}
+	case key.Matches(msg, constants.Keymap.Toggle):
+		if m.isAuthorized(m.candidates[m.pickerCursor].PublicKey) {
+			m.feedback = fmt.Sprintf("The key of %s is already authorized", m.candidates[m.pickerCursor].Reference)
+			return
+		}
+		m.picked[m.pickerCursor] = !m.picked[m.pickerCursor]
+		m.feedback = ""
+	case key.Matches(msg, constants.Keymap.Enter):
+		var added, duplicates []string
+		for i, candidate := range m.candidates {
+			if !m.picked[i] {
+				continue
+			}
+			// the same key may be listed twice, as a service and as a contact
+			if m.isAuthorized(candidate.PublicKey) {
+				duplicates = append(duplicates, candidate.Reference)
+				continue
+			}
+			m.authorizedKeys = append(m.authorizedKeys, candidate.PublicKey)
+			added = append(added, candidate.Reference)
+		}
+		m.picking = false
+		m.feedback = fmt.Sprintf("%d authorized keys added", len(added))
+		if len(duplicates) > 0 {
+			m.feedback += fmt.Sprintf(", already authorized: %s", strings.Join(duplicates, ", "))
+		}
+	}
+}
+
+// isAuthorized returns true when the public key is already in the list of authorized keys
+func (m OwnershipsModel) isAuthorized(publicKey string) bool {
+	for _, authorizedKey := range m.authorizedKeys {
+		if strings.EqualFold(authorizedKey, publicKey) {
+			return true
+		}
+	}
+	return false
+}
+
 func addAuthorizedKey(m *OwnershipsModel) error {
 	authorizedKey := m.ownershipsInputs[1].Value()
 	_, err := hex.DecodeString(authorizedKey)
 	if err != nil {
 		return errors.New("invalid authorization key")
 	}
+	if m.isAuthorized(authorizedKey) {
+		return errors.New("this key is already authorized")
+	}
 	m.authorizedKeys = append(m.authorizedKeys, authorizedKey)
 	m.ownershipsInputs[1].SetValue("")
 	return nil
@@ -236,23 +397,23 @@ func updateOwnershipsFocus(m OwnershipsModel) (OwnershipsModel, []tea.Cmd) {
 		}
 		// Remove focused state
 		m.ownershipsInputs[i].Blur()
-		m.ownershipsInputs[i].PromptStyle = noStyle
-		m.ownershipsInputs[i].TextStyle = noStyle
+		m.ownershipsInputs[i].PromptStyle = lipgloss.NewStyle()
+		m.ownershipsInputs[i].TextStyle = lipgloss.NewStyle()
 	}
 	return m, cmds
 }
 
-func updateOwnershipsFocusInput(m *OwnershipsModel, keypress string) {
-	if keypress == "up" {
+func updateOwnershipsFocusInput(m *OwnershipsModel, up bool) {
+	if up {
 		m.focusInput--
 	} else {
 		m.focusInput++
 	}
 
-	if m.focusInput > len(m.ownershipsInputs)+len(m.authorizedKeys)+len(m.transaction.Data.Ownerships)+2 { // 2 for the 3 buttons
+	if m.focusInput > len(m.ownershipsInputs)+len(m.authorizedKeys)+len(m.transaction.Data.Ownerships)+4 { // 4 for the 5 buttons
 		m.focusInput = 0
 	} else if m.focusInput < 0 {
-		m.focusInput = len(m.ownershipsInputs) + len(m.authorizedKeys) + len(m.transaction.Data.Ownerships) + 2
+		m.focusInput = len(m.ownershipsInputs) + len(m.authorizedKeys) + len(m.transaction.Data.Ownerships) + 4
 	}
 
 }
@@ -263,8 +424,42 @@ func (m *OwnershipsModel) SwitchTab() (OwnershipsModel, []tea.Cmd) {
 	return m2, cmds
 }
 
+// Editing returns true when the user is typing in a field or picking keys
+func (m OwnershipsModel) Editing() bool {
+	if m.picking {
+		return true
+	}
+	for _, input := range m.ownershipsInputs {
+		if input.Focused() {
+			return true
+		}
+	}
+	return false
+}
+
 func (m OwnershipsModel) View() string {
 	var b strings.Builder
+
+	if m.picking {
+		b.WriteString("Select the keys to authorize:\n\n")
+		for i, candidate := range m.candidates {
+			checkbox := "[ ]"
+			if m.picked[i] {
+				checkbox = "[x]"
+			}
+			line := fmt.Sprintf("%s %s %s", checkbox, candidate.Reference, candidate.PublicKey)
+			if m.isAuthorized(candidate.PublicKey) {
+				line = constants.Theme.Blurred.Render(line + " (authorized)")
+			}
+			b.WriteString(constants.Theme.Row(line, i == m.pickerCursor) + "\n")
+		}
+		if m.feedback != "" {
+			fmt.Fprintf(&b, "\n%s\n", m.feedback)
+		}
+		b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("\npress '%s' to select a key, '%s' to add the selected keys, '%s' to cancel ",
+			constants.Keymap.Toggle.Help().Key, constants.Keymap.Enter.Help().Key, constants.Keymap.Back.Help().Key)))
+		return b.String()
+	}
 	for i := range m.ownershipsInputs {
 		b.WriteString(m.ownershipsInputs[i].View())
 		if i < len(m.ownershipsInputs)-1 {
@@ -276,54 +471,40 @@ func (m OwnershipsModel) View() string {
 	if len(m.authorizedKeys) > 0 {
 		b.WriteString("\nList of authorized keys to add:\n")
 		for i := range m.authorizedKeys {
-			if m.focusInput == len(m.ownershipsInputs)+i {
-				b.WriteString(focusedStyle.Render(m.authorizedKeys[i]))
-			} else {
-				b.WriteString(m.authorizedKeys[i])
-			}
+			b.WriteString(constants.Theme.Row(m.authorizedKeys[i], m.focusInput == len(m.ownershipsInputs)+i))
 			b.WriteRune('\n')
 		}
-		b.WriteString(helpStyle.Render("\npress 'd' to delete the selected authorized key "))
+		b.WriteString(constants.Theme.Help.Render("\n" + constants.DeleteHelp("authorized key")))
 	}
 
-	buttonAddAuthKey := &blurredAddAuthKey
-	if m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys) {
-		buttonAddAuthKey = &focusedAddAuthKey
-	}
-	fmt.Fprintf(&b, "\n\n%s", *buttonAddAuthKey)
+	buttonAddAuthKey := constants.Theme.Button("Add authorization key", m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys))
+	buttonPickAuthKeys := constants.Theme.Button("Pick authorization keys", m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys)+1)
+	fmt.Fprintf(&b, "\n\n%s", constants.Columns(m.width, buttonAddAuthKey, buttonPickAuthKeys))
 
-	buttonLoadStorageNouncePK := &blurredLoadStorageNouncePK
-	if m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys)+1 {
-		buttonLoadStorageNouncePK = &focusedLoadStorageNouncePK
-	}
+	buttonLoadStorageNouncePK := constants.Theme.Button("Load Storage Nounce Public Key", m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys)+2)
 	b.WriteString("\n\n")
 	if m.showSpinner {
 		b.WriteString(m.Spinner.View())
 	}
-	fmt.Fprintf(&b, "%s", *buttonLoadStorageNouncePK)
+	fmt.Fprintf(&b, "%s", buttonLoadStorageNouncePK)
 
-	button := &blurredButton
-	if m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys)+2 {
-		button = &focusedButton
-	}
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+	buttonLoadSecret := constants.Theme.Button("Load secret", m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys)+3)
+	fmt.Fprintf(&b, "\n\n%s", buttonLoadSecret)
+
+	button := constants.Theme.Button("Add", m.focusInput == len(m.ownershipsInputs)+len(m.authorizedKeys)+4)
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
 
-	startCount := len(m.ownershipsInputs) + len(m.authorizedKeys) + 3 // +3 for the buttons
+	startCount := len(m.ownershipsInputs) + len(m.authorizedKeys) + 5 // +5 for the buttons
 	for i, o := range m.transaction.Data.Ownerships {
 		ownerships := "**** "
 		for j := range o.AuthorizedKeys {
 			keyHex := hex.EncodeToString(o.AuthorizedKeys[j].PublicKey)
 			ownerships += fmt.Sprintf("%s\n", keyHex)
 		}
-		if m.focusInput == startCount+i {
-			b.WriteString(focusedStyle.Render(ownerships))
-			continue
-		} else {
-			b.WriteString(ownerships)
-		}
+		b.WriteString(constants.Theme.Row(ownerships, m.focusInput == startCount+i))
 	}
 	if len(m.transaction.Data.Ownerships) > 0 {
-		b.WriteString(helpStyle.Render("\npress 'd' to delete the selected ownership "))
+		b.WriteString(constants.Theme.Help.Render("\n" + constants.DeleteHelp("ownership")))
 	}
 	return b.String()
 }
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type RecipientsModel struct {
@@ -27,7 +30,7 @@ type DeleteRecipient struct {
 func NewRecipientsModel(transaction *archethic.TransactionBuilder) RecipientsModel {
 	m := RecipientsModel{transaction: transaction}
 	m.recipientsInput = textinput.New()
-	m.recipientsInput.CursorStyle = cursorStyle
+	m.recipientsInput.CursorStyle = constants.Theme.Cursor
 	m.recipientsInput.Prompt = "> Recipient address:\n"
 	return m
 
@@ -40,12 +43,12 @@ func (m RecipientsModel) Init() tea.Cmd {
 func (m RecipientsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
+		switch {
 
-		case "up", "down":
-			updateRecipientsFocusInput(&m, keypress)
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
+			updateRecipientsFocusInput(&m, key.Matches(msg, constants.Keymap.Up))
 
-		case "enter":
+		case key.Matches(msg, constants.Keymap.Enter):
 
 			if m.focusInput == 1 || m.focusInput == 0 {
 				m.feedback = ""
@@ -63,7 +66,7 @@ func (m RecipientsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "d":
+		case key.Matches(msg, constants.Keymap.Delete):
 
 			if m.focusInput > 1 {
 				indexToDelete := m.focusInput - 2
@@ -95,15 +98,15 @@ func updateRecipientsFocus(m RecipientsModel) (RecipientsModel, []tea.Cmd) {
 		cmds = append(cmds, m.recipientsInput.Focus())
 	} else {
 		m.recipientsInput.Blur()
-		m.recipientsInput.PromptStyle = noStyle
-		m.recipientsInput.TextStyle = noStyle
+		m.recipientsInput.PromptStyle = lipgloss.NewStyle()
+		m.recipientsInput.TextStyle = lipgloss.NewStyle()
 	}
 
 	return m, cmds
 }
 
-func updateRecipientsFocusInput(m *RecipientsModel, keypress string) {
-	if keypress == "up" {
+func updateRecipientsFocusInput(m *RecipientsModel, up bool) {
+	if up {
 		m.focusInput--
 	} else {
 		m.focusInput++
@@ -128,24 +131,16 @@ func (m RecipientsModel) View() string {
 	b.WriteRune('\n')
 	b.WriteString(m.feedback)
 	b.WriteRune('\n')
-	button := &blurredButton
-	if m.focusInput == 1 {
-		button = &focusedButton
-	}
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+	button := constants.Theme.Button("Add", m.focusInput == 1)
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
 
 	startCount := 2 // 1 for the input, 1 for the button
 	for i, t := range m.transaction.Data.Recipients {
 		recipient := fmt.Sprintf("%s\n", hex.EncodeToString(t))
-		if m.focusInput == startCount+i {
-			b.WriteString(focusedStyle.Render(recipient))
-			continue
-		} else {
-			b.WriteString(recipient)
-		}
+		b.WriteString(constants.Theme.Row(recipient, m.focusInput == startCount+i))
 	}
 	if len(m.transaction.Data.Recipients) > 0 {
-		b.WriteString(helpStyle.Render("\npress 'd' to delete the selected recipient "))
+		b.WriteString(constants.Theme.Help.Render("\n" + constants.DeleteHelp("recipient")))
 	}
 	return b.String()
 }
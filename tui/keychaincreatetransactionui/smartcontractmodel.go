@@ -2,22 +2,55 @@ package keychaincreatetransactionui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	focusedPasteSmartContractButton = focusedStyle.Copy().Render("[ Paste ]")
-	blurredPasteSmartContractButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Paste"))
+// contractTokenStyles returns the styles of the tokens of the code, by their kind, in the current theme
+func contractTokenStyles() map[tuiutils.ContractTokenKind]lipgloss.Style {
+	syntax := constants.Theme.Syntax
+	return map[tuiutils.ContractTokenKind]lipgloss.Style{
+		tuiutils.TokenKeyword: syntax.Keyword,
+		tuiutils.TokenKey:     syntax.Key,
+		tuiutils.TokenModule:  syntax.Module,
+		tuiutils.TokenAtom:    syntax.Atom,
+		tuiutils.TokenString:  syntax.String,
+		tuiutils.TokenNumber:  syntax.Number,
+		tuiutils.TokenComment: syntax.Comment,
+		tuiutils.TokenBracket: syntax.Bracket,
+	}
+}
+
+const (
+	SMART_CONTRACT_EDITOR_INDEX = 0
+	SMART_CONTRACT_PATH_INDEX   = 1
+	SMART_CONTRACT_OPEN_INDEX   = 2
+	SMART_CONTRACT_SAVE_INDEX   = 3
+	SMART_CONTRACT_PASTE_INDEX  = 4
+
+	// lines of the tab which are not part of the editor: tabs, borders, buttons, issues and help
+	smartContractChromeHeight = 24
+	minSmartContractHeight    = 5
 )
 
 type SmartContractModel struct {
 	smartContractTextAreaInput textarea.Model
+	pathInput                  textinput.Model
 	focusInput                 int
 	enablePaste                bool
+	issues                     []tuiutils.ContractIssue
+	feedback                   string
+	// width of the tab, the buttons being stacked when it is narrow
+	width int
 }
 
 type UpdateSmartContract struct {
@@ -29,8 +62,13 @@ func NewSmartContractModel() SmartContractModel {
 	m.smartContractTextAreaInput = textarea.New()
 	m.smartContractTextAreaInput.CharLimit = 0
 	m.smartContractTextAreaInput.MaxHeight = 0
+	m.smartContractTextAreaInput.ShowLineNumbers = true
 	m.smartContractTextAreaInput.SetHeight(20)
 	m.smartContractTextAreaInput.SetWidth(150)
+	m.pathInput = textinput.New()
+	m.pathInput.Placeholder = "contract.exs"
+	m.pathInput.Prompt = "> File: "
+	m.pathInput.CursorStyle = constants.Theme.Cursor
 	_, err := clipboard.ReadAll()
 	if err != nil {
 		m.enablePaste = false
@@ -46,65 +84,133 @@ func (m SmartContractModel) Init() tea.Cmd {
 
 func (m SmartContractModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "esc":
-
-			if m.smartContractTextAreaInput.Focused() {
+		// while editing, all the keys are typed in the editor
+		if m.smartContractTextAreaInput.Focused() {
+			if key.Matches(msg, constants.Keymap.Back) {
 				m.smartContractTextAreaInput.Blur()
+				return m, nil
 			}
-			return m, nil
+			return updateSmartContractValue(&m, msg)
+		}
 
-		case "up", "down":
-			if !m.smartContractTextAreaInput.Focused() && m.enablePaste {
-				updateSmartContractFocusInput(&m, keypress)
-			} else {
-				return updateSmartContractValue(&m, msg)
-			}
+		switch {
+		case key.Matches(msg, constants.Keymap.Back):
+			return m, nil
 
-		case "enter":
-			// Paste button
-			if m.focusInput == 1 && m.enablePaste {
-				if !m.smartContractTextAreaInput.Focused() {
-					m.smartContractTextAreaInput.Focus()
-					m.focusInput = 0
-				}
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
+			updateSmartContractFocusInput(&m, key.Matches(msg, constants.Keymap.Up))
 
-				newText := textarea.Paste()
-				return updateSmartContractValue(&m, newText)
-			} else {
-				return updateSmartContractValue(&m, msg)
+		case key.Matches(msg, constants.Keymap.Enter):
+			switch m.focusInput {
+			case SMART_CONTRACT_EDITOR_INDEX:
+				m.smartContractTextAreaInput.Focus()
+			case SMART_CONTRACT_OPEN_INDEX:
+				return m.open()
+			case SMART_CONTRACT_SAVE_INDEX:
+				m.save()
+			case SMART_CONTRACT_PASTE_INDEX:
+				m.focusInput = SMART_CONTRACT_EDITOR_INDEX
+				m.smartContractTextAreaInput.Focus()
+				return updateSmartContractValue(&m, textarea.Paste())
 			}
 		default:
-
-			if !m.smartContractTextAreaInput.Focused() {
+			switch m.focusInput {
+			case SMART_CONTRACT_PATH_INDEX:
+				var cmd tea.Cmd
+				m.pathInput, cmd = m.pathInput.Update(msg)
+				return m, cmd
+			case SMART_CONTRACT_EDITOR_INDEX:
 				m.smartContractTextAreaInput.Focus()
-				m.focusInput = 0
+				return updateSmartContractValue(&m, msg)
 			}
-			return updateSmartContractValue(&m, msg)
 		}
 	}
 
 	return m, nil
 }
 
+// setSize fits the editor in the window
+func (m *SmartContractModel) setSize(width int, height int) {
+	m.width = tabWidth(width)
+	m.smartContractTextAreaInput.SetWidth(m.width)
+	editorHeight := height - smartContractChromeHeight
+	if editorHeight < minSmartContractHeight {
+		editorHeight = minSmartContractHeight
+	}
+	m.smartContractTextAreaInput.SetHeight(editorHeight)
+}
+
+// SetCode replaces the code of the editor
+func (m *SmartContractModel) SetCode(code string) {
+	m.smartContractTextAreaInput.SetValue(code)
+	m.issues = tuiutils.CheckContractBalance(code)
+}
+
+// open loads the code from the file
+func (m SmartContractModel) open() (SmartContractModel, tea.Cmd) {
+	path := m.pathInput.Value()
+	code, err := os.ReadFile(path)
+	if err != nil {
+		m.feedback = err.Error()
+		return m, nil
+	}
+	m.SetCode(string(code))
+	m.feedback = "Smart contract loaded from " + path
+	return m, func() tea.Msg {
+		return UpdateSmartContract{Code: string(code)}
+	}
+}
+
+// save writes the code to the file
+func (m *SmartContractModel) save() {
+	path := m.pathInput.Value()
+	if path == "" {
+		m.feedback = "the file path is required"
+		return
+	}
+	err := os.WriteFile(path, []byte(m.smartContractTextAreaInput.Value()), 0644)
+	if err != nil {
+		m.feedback = err.Error()
+		return
+	}
+	m.feedback = "Smart contract saved to " + path
+}
+
 func updateSmartContractValue(m *SmartContractModel, msg tea.Msg) (SmartContractModel, func() tea.Msg) {
 	m.smartContractTextAreaInput, _ = m.smartContractTextAreaInput.Update(msg)
+	code := m.smartContractTextAreaInput.Value()
+	m.issues = tuiutils.CheckContractBalance(code)
 	return *m, func() tea.Msg {
-		return UpdateSmartContract{Code: m.smartContractTextAreaInput.Value()}
+		return UpdateSmartContract{Code: code}
 	}
 }
 
-func updateSmartContractFocusInput(m *SmartContractModel, keypress string) {
-	if keypress == "up" {
+func updateSmartContractFocusInput(m *SmartContractModel, up bool) {
+	last := SMART_CONTRACT_SAVE_INDEX
+	if m.enablePaste {
+		last = SMART_CONTRACT_PASTE_INDEX
+	}
+	if up {
 		m.focusInput--
 	} else {
 		m.focusInput++
 	}
-	if m.focusInput > 1 {
+	if m.focusInput > last {
 		m.focusInput = 0
 	} else if m.focusInput < 0 {
-		m.focusInput = 1
+		m.focusInput = last
+	}
+	if m.focusInput == SMART_CONTRACT_PATH_INDEX {
+		m.pathInput.Focus()
+		m.pathInput.PromptStyle = constants.Theme.Focused
+		m.pathInput.TextStyle = constants.Theme.Focused
+	} else {
+		m.pathInput.Blur()
+		m.pathInput.PromptStyle = lipgloss.NewStyle()
+		m.pathInput.TextStyle = lipgloss.NewStyle()
 	}
 }
 
@@ -112,19 +218,81 @@ func (m *SmartContractModel) SwitchTab() (SmartContractModel, []tea.Cmd) {
 	return *m, nil
 }
 
+// Editing returns true when the user is typing in the editor or in the file path
+func (m SmartContractModel) Editing() bool {
+	return m.smartContractTextAreaInput.Focused() || m.pathInput.Focused()
+}
+
+// highlightedView renders the code with its syntax highlighted, around the line of the cursor
+func (m SmartContractModel) highlightedView() string {
+	lines := tuiutils.TokenizeContract(m.smartContractTextAreaInput.Value())
+	height := m.smartContractTextAreaInput.Height()
+	start := m.smartContractTextAreaInput.Line() - height/2
+	if start > len(lines)-height {
+		start = len(lines) - height
+	}
+	if start < 0 {
+		start = 0
+	}
+	numberWidth := len(fmt.Sprint(len(lines)))
+	lineStyle := lipgloss.NewStyle().MaxWidth(m.smartContractTextAreaInput.Width() + numberWidth + 2)
+	tokenStyles := contractTokenStyles()
+
+	var b strings.Builder
+	for i := start; i < start+height; i++ {
+		if i >= len(lines) {
+			b.WriteString("\n")
+			continue
+		}
+		var line strings.Builder
+		line.WriteString(constants.Theme.Syntax.LineNumber.Render(fmt.Sprintf("%*d ", numberWidth, i+1)))
+		for _, token := range lines[i] {
+			if style, ok := tokenStyles[token.Kind]; ok {
+				line.WriteString(style.Render(token.Text))
+			} else {
+				line.WriteString(token.Text)
+			}
+		}
+		b.WriteString(lineStyle.Render(line.String()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 func (m SmartContractModel) View() string {
 	var b strings.Builder
 
-	b.WriteString(m.smartContractTextAreaInput.View())
 	if m.smartContractTextAreaInput.Focused() {
-		b.WriteString(helpStyle.Render("\npress 'esc' to exit edit mode "))
+		b.WriteString(m.smartContractTextAreaInput.View())
+		b.WriteString(constants.Theme.Help.Render(fmt.Sprintf("\npress '%s' to exit edit mode ", constants.Keymap.Back.Help().Key)))
+	} else {
+		editor := m.highlightedView()
+		if m.focusInput == SMART_CONTRACT_EDITOR_INDEX {
+			editor += constants.Theme.Help.Render(fmt.Sprintf("press '%s' or type to edit ", constants.Keymap.Enter.Help().Key))
+		}
+		b.WriteString(editor)
 	}
-	button := &blurredPasteSmartContractButton
-	if m.focusInput == 1 {
-		button = &focusedPasteSmartContractButton
+	b.WriteString("\n")
+
+	if len(m.issues) == 0 {
+		b.WriteString(constants.Theme.Blurred.Render("Brackets and blocks are balanced"))
+		b.WriteString("\n")
 	}
+	for _, issue := range m.issues {
+		b.WriteString(constants.ErrStyle(issue.String()))
+		b.WriteString("\n")
+	}
+
+	openButton := constants.Theme.Button("Open", m.focusInput == SMART_CONTRACT_OPEN_INDEX)
+	saveButton := constants.Theme.Button("Save", m.focusInput == SMART_CONTRACT_SAVE_INDEX)
+	pasteButton := constants.Theme.Button("Paste", m.focusInput == SMART_CONTRACT_PASTE_INDEX)
+	buttons := []string{openButton, saveButton}
 	if m.enablePaste {
-		fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+		buttons = append(buttons, pasteButton)
+	}
+	fmt.Fprintf(&b, "\n%s\n\n%s", m.pathInput.View(), constants.Columns(m.width, buttons...))
+	if m.feedback != "" {
+		fmt.Fprintf(&b, "\n\n%s", m.feedback)
 	}
 	return b.String()
 }
@@ -0,0 +1,122 @@
+package keychaincreatetransactionui
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/archethic-foundation/archethic-cli/cli"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// saveTemplate writes the transaction being built to a YAML template, without the seed and the ownerships' secrets
+func saveTemplate(m *Model, path string, curve archethic.Curve) error {
+	data := cli.TemplateFromTransaction(&m.transaction, m.url, curve, m.serviceName, m.contentModel.source(), m.contentModel.encoding)
+	return cli.WriteTransactionTemplate(path, data)
+}
+
+// loadTemplate replaces the transaction being built by the one described in the YAML template.
+// The ownerships without secret are queued in the ownerships tab, waiting for the user to type their secret.
+func loadTemplate(m *Model, path string) error {
+	data, err := cli.ReadTransactionTemplate(path)
+	if err != nil {
+		return err
+	}
+
+	// build the transaction before touching the interface, so an invalid template leaves it unchanged
+	transaction := archethic.NewTransaction(m.transaction.TxType)
+	if data.TransactionType != "" {
+		var transactionType cli.TransactionTypeCLI
+		if err := transactionType.Set(data.TransactionType); err != nil {
+			return err
+		}
+		txType, err := transactionType.GetTransactionType()
+		if err != nil {
+			return err
+		}
+		transaction.SetType(txType)
+	}
+	for _, transfer := range data.UcoTransfers {
+		to, err := hex.DecodeString(transfer.To)
+		if err != nil {
+			return fmt.Errorf("invalid UCO transfer recipient %s", transfer.To)
+		}
+		transaction.AddUcoTransfer(to, cli.ToBigInt(transfer.Amount, 8))
+	}
+	for _, transfer := range data.TokenTransfers {
+		to, err := hex.DecodeString(transfer.To)
+		if err != nil {
+			return fmt.Errorf("invalid token transfer recipient %s", transfer.To)
+		}
+		tokenAddress, err := hex.DecodeString(transfer.TokenAddress)
+		if err != nil {
+			return fmt.Errorf("invalid token address %s", transfer.TokenAddress)
+		}
+		transaction.AddTokenTransfer(to, tokenAddress, cli.ToBigInt(transfer.Amount, 8), transfer.TokenID)
+	}
+	for _, recipient := range data.Recipients {
+		recipientBytes, err := hex.DecodeString(recipient)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %s", recipient)
+		}
+		transaction.AddRecipient(recipientBytes)
+	}
+	var pendingOwnerships []pendingOwnership
+	for _, ownership := range data.Ownerships {
+		if ownership.Secret == "" {
+			pendingOwnerships = append(pendingOwnerships, pendingOwnership{authorizedKeys: ownership.AuthorizedKeys})
+			continue
+		}
+		cipher, authorizedKeys, err := tuiutils.EncryptOwnership([]byte(ownership.Secret), ownership.AuthorizedKeys, m.secretKey)
+		if err != nil {
+			return err
+		}
+		transaction.AddOwnership(cipher, authorizedKeys)
+	}
+	contentEncoding, err := tuiutils.ParseContentEncoding(data.ContentEncoding)
+	if err != nil {
+		return err
+	}
+	var curve cli.CurveCLI
+	if data.EllipticCurve != "" {
+		if err := curve.Set(data.EllipticCurve); err != nil {
+			return err
+		}
+	}
+
+	// keep the seed typed by the user, it is never part of a template
+	seed := m.mainModel.mainInputs[SEED_INPUT].Value()
+	templatePath := m.mainModel.mainInputs[TEMPLATE_PATH_INPUT].Value()
+	m.resetInterface(m.pvKeyBytes)
+	m.transaction = *transaction
+	m.ucoTransferModel.transaction = &m.transaction
+	m.tokenTransferModel.transaction = &m.transaction
+	m.recipientsModel.transaction = &m.transaction
+	m.ownershipsModel.transaction = &m.transaction
+	m.ownershipsModel.setPendingOwnerships(pendingOwnerships)
+	content, err := m.contentModel.SetContent([]byte(data.Content), contentEncoding)
+	if err != nil {
+		return err
+	}
+	m.transaction.SetContent(content)
+	m.smartContractModel.SetCode(data.SmartContract)
+	m.transaction.SetCode(data.SmartContract)
+
+	m.mainModel.mainInputs[SEED_INPUT].SetValue(seed)
+	m.mainModel.mainInputs[TEMPLATE_PATH_INPUT].SetValue(templatePath)
+	for name, txType := range transactionTypes {
+		if txType == m.transaction.TxType {
+			m.mainModel.selectedTransactionType = name
+		}
+	}
+	// the endpoint and the curve of a service come from the keychain
+	if !m.serviceMode {
+		m.mainModel.setUrl(data.Endpoint)
+		m.url = data.Endpoint
+		m.ownershipsModel.SetUrl(data.Endpoint)
+		if data.EllipticCurve != "" {
+			m.mainModel.mainInputs[CURVE_INPUT].SetValue(fmt.Sprint(uint8(curve)))
+		}
+	}
+	return nil
+}
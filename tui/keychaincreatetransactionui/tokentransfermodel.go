@@ -7,9 +7,13 @@ import (
 	"strings"
 
 	"github.com/archethic-foundation/archethic-cli/cli"
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type TokenTransferModel struct {
@@ -38,7 +42,7 @@ func NewTokenTransferModel(transaction *archethic.TransactionBuilder) TokenTrans
 	}
 	for i := range m.tokenInputs {
 		t := textinput.New()
-		t.CursorStyle = cursorStyle
+		t.CursorStyle = constants.Theme.Cursor
 		switch i {
 		case 0:
 			t.Prompt = "> To:\n"
@@ -64,11 +68,11 @@ func (m TokenTransferModel) Init() tea.Cmd {
 func (m TokenTransferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "up", "down":
-			updateTokenTransferFocusInput(&m, keypress)
+		switch {
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
+			updateTokenTransferFocusInput(&m, key.Matches(msg, constants.Keymap.Up))
 
-		case "enter":
+		case key.Matches(msg, constants.Keymap.Enter):
 			if m.focusInput == len(m.tokenInputs) {
 				toHex := m.tokenInputs[0].Value()
 				to, err := hex.DecodeString(toHex)
@@ -107,7 +111,7 @@ func (m TokenTransferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return AddTokenTransfer{To: to, Amount: amountBigInt, TokenAddress: tokenAddress, TokenId: tokenId, cmds: cmds}
 				}
 			}
-		case "d":
+		case key.Matches(msg, constants.Keymap.Delete):
 			if m.focusInput > len(m.tokenInputs) {
 				indexToDelete := m.focusInput - len(m.tokenInputs) - 1
 				m.focusInput--
@@ -119,10 +123,33 @@ func (m TokenTransferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	m, cmds := updateTokenTransferFocus(m)
 	cmds = append(cmds, m.updateTokenTransferInputs(msg)...)
+	m.applyPaymentRequest()
 
 	return m, tea.Batch(cmds...)
 }
 
+// applyPaymentRequest fills the inputs from a payment request URI pasted in the To input
+func (m *TokenTransferModel) applyPaymentRequest() {
+	uri := m.tokenInputs[0].Value()
+	if !tuiutils.IsPaymentURI(uri) {
+		return
+	}
+	request, err := tuiutils.ParsePaymentRequest(uri)
+	if err != nil {
+		m.feedback = err.Error()
+		return
+	}
+	m.tokenInputs[0].SetValue(request.Address)
+	if request.Amount > 0 {
+		m.tokenInputs[1].SetValue(tuiutils.FormatAmount(request.Amount))
+	}
+	if request.TokenAddress != "" {
+		m.tokenInputs[2].SetValue(request.TokenAddress)
+		m.tokenInputs[3].SetValue(strconv.Itoa(request.TokenId))
+	}
+	m.feedback = paymentRequestFeedback(request)
+}
+
 func (m *TokenTransferModel) updateTokenTransferInputs(msg tea.Msg) []tea.Cmd {
 
 	cmds := make([]tea.Cmd, len(m.tokenInputs))
@@ -144,15 +171,15 @@ func updateTokenTransferFocus(m TokenTransferModel) (TokenTransferModel, []tea.C
 		}
 		// Remove focused state
 		m.tokenInputs[i].Blur()
-		m.tokenInputs[i].PromptStyle = noStyle
-		m.tokenInputs[i].TextStyle = noStyle
+		m.tokenInputs[i].PromptStyle = lipgloss.NewStyle()
+		m.tokenInputs[i].TextStyle = lipgloss.NewStyle()
 	}
 
 	return m, cmds
 }
 
-func updateTokenTransferFocusInput(m *TokenTransferModel, keypress string) {
-	if keypress == "up" {
+func updateTokenTransferFocusInput(m *TokenTransferModel, up bool) {
+	if up {
 		m.focusInput--
 	} else {
 		m.focusInput++
@@ -180,24 +207,16 @@ func (m TokenTransferModel) View() string {
 	}
 	b.WriteRune('\n')
 	b.WriteString(m.feedback)
-	button := &blurredButton
-	if m.focusInput == len(m.tokenInputs) {
-		button = &focusedButton
-	}
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+	button := constants.Theme.Button("Add", m.focusInput == len(m.tokenInputs))
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
 
 	startCount := len(m.tokenInputs) + 1 // +1 for the button
 	for i, t := range m.transaction.Data.Ledger.Token.Transfers {
 		transfer := fmt.Sprintf("%s : %f - %s %d \n", hex.EncodeToString(t.To), cli.FromBigInt(t.Amount, 8), hex.EncodeToString(t.TokenAddress), t.TokenId)
-		if m.focusInput == startCount+i {
-			b.WriteString(focusedStyle.Render(transfer))
-			continue
-		} else {
-			b.WriteString(transfer)
-		}
+		b.WriteString(constants.Theme.Row(transfer, m.focusInput == startCount+i))
 	}
 	if len(m.transaction.Data.Ledger.Token.Transfers) > 0 {
-		b.WriteString(helpStyle.Render("\npress 'd' to delete the selected token transfer "))
+		b.WriteString(constants.Theme.Help.Render("\n" + constants.DeleteHelp("token transfer")))
 	}
 	return b.String()
 }
@@ -7,9 +7,13 @@ import (
 	"strings"
 
 	"github.com/archethic-foundation/archethic-cli/cli"
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type UcoTransferModel struct {
@@ -36,7 +40,7 @@ func NewUcoTransferModel(transaction *archethic.TransactionBuilder) UcoTransferM
 	}
 	for i := range m.ucoInputs {
 		t := textinput.New()
-		t.CursorStyle = cursorStyle
+		t.CursorStyle = constants.Theme.Cursor
 		switch i {
 		case 0:
 			t.Prompt = "> To:\n"
@@ -59,12 +63,12 @@ func (m UcoTransferModel) Init() tea.Cmd {
 func (m UcoTransferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
+		switch {
 
-		case "up", "down":
-			updateUcoTransferFocusInput(&m, keypress)
+		case key.Matches(msg, constants.Keymap.Up, constants.Keymap.Down):
+			updateUcoTransferFocusInput(&m, key.Matches(msg, constants.Keymap.Up))
 
-		case "enter":
+		case key.Matches(msg, constants.Keymap.Enter):
 
 			if m.focusInput == len(m.ucoInputs) {
 				m.feedback = ""
@@ -95,7 +99,7 @@ func (m UcoTransferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
-		case "d":
+		case key.Matches(msg, constants.Keymap.Delete):
 
 			if m.focusInput > len(m.ucoInputs) {
 				indexToDelete := m.focusInput - len(m.ucoInputs) - 1
@@ -112,10 +116,36 @@ func (m UcoTransferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	m, cmds := updateUcoTransferFocus(m)
 	cmds = append(cmds, m.updateUcoTransferInputs(msg)...)
+	m.applyPaymentRequest()
 
 	return m, tea.Batch(cmds...)
 }
 
+// applyPaymentRequest fills the inputs from a payment request URI pasted in the To input
+func (m *UcoTransferModel) applyPaymentRequest() {
+	uri := m.ucoInputs[0].Value()
+	if !tuiutils.IsPaymentURI(uri) {
+		return
+	}
+	request, err := tuiutils.ParsePaymentRequest(uri)
+	if err != nil {
+		m.feedback = err.Error()
+		return
+	}
+	// the amount of a token request is an amount of tokens: the inputs are cleared so no UCO are sent by mistake
+	if request.TokenAddress != "" {
+		m.ucoInputs[0].SetValue("")
+		m.ucoInputs[1].SetValue("")
+		m.feedback = fmt.Sprintf("The payment request asks for the token %s (id %d): paste it in the Token transfers tab", request.TokenAddress, request.TokenId)
+		return
+	}
+	m.ucoInputs[0].SetValue(request.Address)
+	if request.Amount > 0 {
+		m.ucoInputs[1].SetValue(tuiutils.FormatAmount(request.Amount))
+	}
+	m.feedback = paymentRequestFeedback(request)
+}
+
 func (m *UcoTransferModel) updateUcoTransferInputs(msg tea.Msg) []tea.Cmd {
 
 	cmds := make([]tea.Cmd, len(m.ucoInputs))
@@ -137,15 +167,15 @@ func updateUcoTransferFocus(m UcoTransferModel) (UcoTransferModel, []tea.Cmd) {
 		}
 		// Remove focused state
 		m.ucoInputs[i].Blur()
-		m.ucoInputs[i].PromptStyle = noStyle
-		m.ucoInputs[i].TextStyle = noStyle
+		m.ucoInputs[i].PromptStyle = lipgloss.NewStyle()
+		m.ucoInputs[i].TextStyle = lipgloss.NewStyle()
 	}
 
 	return m, cmds
 }
 
-func updateUcoTransferFocusInput(m *UcoTransferModel, keypress string) {
-	if keypress == "up" {
+func updateUcoTransferFocusInput(m *UcoTransferModel, up bool) {
+	if up {
 		m.focusInput--
 	} else {
 		m.focusInput++
@@ -175,24 +205,16 @@ func (m UcoTransferModel) View() string {
 	}
 	b.WriteRune('\n')
 	b.WriteString(m.feedback)
-	button := &blurredButton
-	if m.focusInput == len(m.ucoInputs) {
-		button = &focusedButton
-	}
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+	button := constants.Theme.Button("Add", m.focusInput == len(m.ucoInputs))
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
 
 	startCount := len(m.ucoInputs) + 1 // +1 for the button
 	for i, t := range m.transaction.Data.Ledger.Uco.Transfers {
 		transfer := fmt.Sprintf("%s: %f\n", hex.EncodeToString(t.To), cli.FromBigInt(t.Amount, 8))
-		if m.focusInput == startCount+i {
-			b.WriteString(focusedStyle.Render(transfer))
-			continue
-		} else {
-			b.WriteString(transfer)
-		}
+		b.WriteString(constants.Theme.Row(transfer, m.focusInput == startCount+i))
 	}
 	if len(m.transaction.Data.Ledger.Uco.Transfers) > 0 {
-		b.WriteString(helpStyle.Render("\npress 'd' to delete the selected UCO transfer "))
+		b.WriteString(constants.Theme.Help.Render("\n" + constants.DeleteHelp("UCO transfer")))
 	}
 	return b.String()
 }
@@ -7,9 +7,11 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	"github.com/archethic-foundation/archethic-cli/tui/keychaincreatetransactionui"
 	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
 	archethic "github.com/archethic-foundation/libgo"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -32,25 +34,8 @@ type SendCreateService struct {
 }
 
 var (
-	focusedStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	blurredStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	cursorStyle         = focusedStyle.Copy()
-	noStyle             = lipgloss.NewStyle()
-	helpStyle           = blurredStyle.Copy()
-	cursorModeHelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
-
-	accessFocusedButton = focusedStyle.Copy().Render("[ Access Keychain ]")
-	accessBlurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Access Keychain"))
-
-	createFocusedButton = focusedStyle.Copy().Render("[ Create Keychain ]")
-	createBlurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Create Keychain"))
-
-	createTransactionFocusedButton       = focusedStyle.Copy().Render("[ Create Transaction for Service ]")
-	createTransactionaccessBlurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Create Transaction for Service"))
-	createServiceFocusedButton           = focusedStyle.Copy().Render("[ Create Service ]")
-	createServiceBlurredButton           = fmt.Sprintf("[ %s ]", blurredStyle.Render("Create Service"))
-	urlType                              = []string{"Local", "Testnet", "Mainnet", "Custom"}
-	urls                                 = map[string]string{
+	urlType = []string{"Local", "Testnet", "Mainnet", "Custom"}
+	urls    = map[string]string{
 		"Local":   "http://localhost:4000",
 		"Testnet": "https://testnet.archethic.net",
 		"Mainnet": "https://mainnet.archethic.net",
@@ -76,12 +61,13 @@ type Model struct {
 	showSpinnerCreateService         bool
 	Spinner                          spinner.Model
 	pvKeyBytes                       []byte
+	width                            int
 }
 
 func New(pvKeyBytes []byte) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = constants.Theme.Spinner
 	m := Model{
 		inputs:           make([]textinput.Model, 2),
 		Spinner:          s,
@@ -92,7 +78,7 @@ func New(pvKeyBytes []byte) Model {
 	var t textinput.Model
 	for i := range m.inputs {
 		t = textinput.New()
-		t.CursorStyle = cursorStyle
+		t.CursorStyle = constants.Theme.Cursor
 
 		switch i {
 		case 0:
@@ -112,7 +98,7 @@ func New(pvKeyBytes []byte) Model {
 
 	for i := range m.newServiceInputs {
 		t = textinput.New()
-		t.CursorStyle = cursorStyle
+		t.CursorStyle = constants.Theme.Cursor
 
 		switch i {
 		case 0:
@@ -134,6 +120,9 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	urlBlockSize := len(urlType)
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
 	case SendNewKeychainTransaction:
 		m.feedback = msg.Model.feedback
 		m.keychainSeed = msg.Model.keychainSeed
@@ -164,16 +153,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds := m.updateInputs(msg)
 		return m, tea.Batch(cmds...)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		switch {
+		case key.Matches(msg, constants.Keymap.Quit):
 			return m, tea.Quit
 
-		case "esc":
+		case key.Matches(msg, constants.Keymap.Back):
 			return New(m.pvKeyBytes), func() tea.Msg {
 				return BackMsg(true)
 			}
 
-		case "enter":
+		case key.Matches(msg, constants.Keymap.Enter):
 			if m.focusIndex < urlBlockSize {
 				u := urlType[m.focusIndex]
 				m.inputs[0].SetValue(urls[u])
@@ -240,13 +229,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		// Set focus to next input
-		case "tab", "shift+tab", "up", "down":
-			s := msg.String()
-
+		case key.Matches(msg, constants.Keymap.NextField, constants.Keymap.PrevField, constants.Keymap.Up, constants.Keymap.Down):
 			// Cycle indexes
-			if s == "up" || s == "shift+tab" {
+			if key.Matches(msg, constants.Keymap.Up, constants.Keymap.PrevField) {
 				m.focusIndex--
-			} else if s == "down" || s == "tab" {
+			} else {
 				m.focusIndex++
 			}
 
@@ -267,7 +254,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		default:
 			// remove the highlighted service
-			if msg.String() == "d" && m.focusIndex > len(m.inputs)+urlBlockSize+1 && m.focusIndex < len(m.inputs)+urlBlockSize+2+len(m.serviceNames) {
+			if key.Matches(msg, constants.Keymap.Delete) && m.focusIndex > len(m.inputs)+urlBlockSize+1 && m.focusIndex < len(m.inputs)+urlBlockSize+2+len(m.serviceNames) {
 				selectedService := m.focusIndex - len(m.inputs) - urlBlockSize - 2
 				m.showSpinnerDeleteService = true
 				return m, func() tea.Msg {
@@ -382,8 +369,8 @@ func (m *Model) updateFocus(urlBlockSize int) []tea.Cmd {
 		}
 		// Remove focused state
 		m.inputs[i].Blur()
-		m.inputs[i].PromptStyle = noStyle
-		m.inputs[i].TextStyle = noStyle
+		m.inputs[i].PromptStyle = lipgloss.NewStyle()
+		m.inputs[i].TextStyle = lipgloss.NewStyle()
 	}
 
 	for i := 0; i < len(m.newServiceInputs); i++ {
@@ -395,8 +382,8 @@ func (m *Model) updateFocus(urlBlockSize int) []tea.Cmd {
 		}
 		// Remove focused state
 		m.newServiceInputs[i].Blur()
-		m.newServiceInputs[i].PromptStyle = noStyle
-		m.newServiceInputs[i].TextStyle = noStyle
+		m.newServiceInputs[i].PromptStyle = lipgloss.NewStyle()
+		m.newServiceInputs[i].TextStyle = lipgloss.NewStyle()
 	}
 
 	return cmds
@@ -453,10 +440,7 @@ func (m Model) View() string {
 		b.WriteString(m.Spinner.View())
 	}
 
-	createButton := &createBlurredButton
-	if m.focusIndex == len(m.inputs)+len(urlType) {
-		createButton = &createFocusedButton
-	}
+	createButton := constants.Theme.Button("Create Keychain", m.focusIndex == len(m.inputs)+len(urlType))
 
 	if m.feedback != "" {
 		b.WriteString("\n\n")
@@ -464,7 +448,7 @@ func (m Model) View() string {
 		b.WriteString("\n\n")
 	}
 
-	fmt.Fprintf(&b, "\n\n%s", *createButton)
+	fmt.Fprintf(&b, "\n\n%s", createButton)
 	b.WriteRune('\n')
 	if m.keychainSeed != "" {
 		fmt.Fprintf(&b, "Keychain seed: %s\n", m.keychainSeed)
@@ -476,11 +460,8 @@ func (m Model) View() string {
 		b.WriteString("\n\n")
 		b.WriteString(m.Spinner.View())
 	}
-	button := &accessBlurredButton
-	if m.focusIndex == len(m.inputs)+5 {
-		button = &accessFocusedButton
-	}
-	fmt.Fprintf(&b, "\n\n%s\n\n", *button)
+	button := constants.Theme.Button("Access Keychain", m.focusIndex == len(m.inputs)+5)
+	fmt.Fprintf(&b, "\n\n%s\n\n", button)
 
 	if m.keychain != nil {
 		var b2 strings.Builder
@@ -499,14 +480,10 @@ func (m Model) View() string {
 
 			keychainDerivedAddress, _ := m.keychain.DeriveAddress(k, 0)
 			u += k + " : " + m.keychain.Services[k].DerivationPath + " (" + hex.EncodeToString(keychainDerivedAddress) + ")\n"
-			if m.focusIndex == i+len(m.inputs)+6 {
-				b2.WriteString(focusedStyle.Render(u))
-			} else {
-				b2.WriteString(u)
-			}
+			b2.WriteString(constants.Theme.Row(u, m.focusIndex == i+len(m.inputs)+6))
 			b2.WriteString("\n")
 		}
-		b2.WriteString(helpStyle.Render("press 'enter' to select or 'd' to delete "))
+		b2.WriteString(constants.Theme.Help.Render(fmt.Sprintf("press '%s' to select or '%s' to delete ", constants.Keymap.Enter.Help().Key, constants.Keymap.Delete.Help().Key)))
 
 		if m.showSpinnerDeleteService {
 			b2.WriteString("\n\n")
@@ -514,11 +491,8 @@ func (m Model) View() string {
 		}
 
 		if len(m.serviceNames) > 0 {
-			button := &createTransactionaccessBlurredButton
-			if m.focusIndex == len(m.inputs)+len(m.serviceNames)+6 {
-				button = &createTransactionFocusedButton
-			}
-			fmt.Fprintf(&b2, "\n\n\n%s\n\n", *button)
+			button := constants.Theme.Button("Create Transaction for Service", m.focusIndex == len(m.inputs)+len(m.serviceNames)+6)
+			fmt.Fprintf(&b2, "\n\n\n%s\n\n", button)
 		} else {
 			b2.WriteString("No service")
 		}
@@ -535,34 +509,64 @@ func (m Model) View() string {
 			b2.WriteString(m.Spinner.View())
 		}
 
-		createServiceButton := &createServiceBlurredButton
-		if m.focusIndex == len(m.inputs)+len(m.serviceNames)+6+len(m.newServiceInputs)+1 {
-			createServiceButton = &createServiceFocusedButton
-		}
-		fmt.Fprintf(&b2, "\n\n%s\n\n", *createServiceButton)
+		createServiceButton := constants.Theme.Button("Create Service", m.focusIndex == len(m.inputs)+len(m.serviceNames)+6+len(m.newServiceInputs)+1)
+		fmt.Fprintf(&b2, "\n\n%s\n\n", createServiceButton)
 
+		padding := 10
+		if constants.Narrow(m.width) {
+			padding = 1
+		}
 		dialogBoxStyle := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#874BFD")).
-			Padding(1, 10).
+			BorderForeground(constants.Theme.Border).
+			Padding(1, padding).
 			BorderTop(true).
 			BorderLeft(true).
 			BorderRight(true).
 			BorderBottom(true)
+		// the services, whose addresses are long, are wrapped in the width of the window
+		if m.width > 0 && lipgloss.Width(dialogBoxStyle.Render(b2.String())) > m.width {
+			dialogBoxStyle = dialogBoxStyle.Width(m.width - dialogBoxStyle.GetHorizontalBorderSize())
+		}
 
-		dialog := lipgloss.Place(150, 9,
-			lipgloss.Left, lipgloss.Center,
-			dialogBoxStyle.Render(b2.String()),
-		)
-		b.WriteString(dialog)
+		b.WriteString(dialogBoxStyle.Render(b2.String()))
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("press 'esc' to go back "))
+	b.WriteString(constants.Theme.Help.Render(constants.BackHelp()))
 
 	return b.String()
 }
 
+// Endpoints returns the endpoints of the keychain
+func (m Model) Endpoints() string {
+	return m.inputs[0].Value()
+}
+
+// Editing returns true when the user is typing in a field
+func (m Model) Editing() bool {
+	for _, input := range m.inputs {
+		if input.Focused() {
+			return true
+		}
+	}
+	for _, input := range m.newServiceInputs {
+		if input.Focused() {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyMap returns the key bindings displayed in the help overlay
+func (m Model) KeyMap() constants.HelpKeyMap {
+	return constants.HelpKeyMap{
+		{constants.Keymap.Up, constants.Keymap.Down, constants.Keymap.NextField, constants.Keymap.PrevField},
+		{constants.Keymap.Enter, constants.Keymap.Delete},
+		{constants.Keymap.Back, constants.Keymap.Help, constants.Keymap.Quit},
+	}
+}
+
 func urlView(m Model) string {
 	s := strings.Builder{}
 
@@ -574,11 +578,7 @@ func urlView(m Model) string {
 			u = "( ) "
 		}
 		u += urlType[i]
-		if i == m.focusIndex {
-			s.WriteString(focusedStyle.Render(u))
-		} else {
-			s.WriteString(u)
-		}
+		s.WriteString(constants.Theme.Row(u, i == m.focusIndex))
 		s.WriteString("\n")
 	}
 
@@ -597,7 +597,7 @@ func getAccessKey(m Model) ([]byte, error) {
 		}
 	}
 
-	accessSeed, err := archethic.MaybeConvertToHex(m.inputs[1].Value())
+	accessSeed, err := tuiutils.DecodeSecret(m.inputs[1].Value())
 	if m.pvKeyBytes != nil {
 		accessSeed = m.pvKeyBytes
 	}
@@ -19,8 +19,13 @@ type Model struct {
 
 // New initialize the projectui model for your program
 func New() tea.Model {
-	m := Model{list: list.NewModel(menu(), list.NewDefaultDelegate(), 100, 25)} //Updated the default width and height to large defaults from 0.
+	// the selected item is also marked by a border on its left, not only by its colour
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Copy().Foreground(constants.Theme.Focused.GetForeground()).BorderForeground(constants.Theme.Border).Bold(true)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Copy().Foreground(constants.Theme.Focused.GetForeground()).BorderForeground(constants.Theme.Border)
+	m := Model{list: list.NewModel(menu(), delegate, 100, 25)} //Updated the default width and height to large defaults from 0.
 	m.list.Title = "Archethic CLI"
+	m.list.Styles.Title = m.list.Styles.Title.Copy().Background(constants.Theme.Border)
 	return m
 }
 
@@ -38,7 +43,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m = m.UpdateWindowSize(msg.Width, msg.Height).(Model)
 	case tea.KeyMsg:
 		switch {
-		case msg.String() == "ctrl+c":
+		case key.Matches(msg, constants.Keymap.Quit):
 			return m, tea.Quit
 		case key.Matches(msg, constants.Keymap.Enter):
 			cmd = selectCmd(uint(m.list.Cursor()))
@@ -55,6 +60,20 @@ func (m Model) View() string {
 	return constants.DocStyle.Render(m.list.View() + "\n")
 }
 
+// Editing returns true when the user is typing in the menu filter
+func (m Model) Editing() bool {
+	return m.list.FilterState() == list.Filtering
+}
+
+// KeyMap returns the key bindings displayed in the help overlay
+func (m Model) KeyMap() constants.HelpKeyMap {
+	return constants.HelpKeyMap{
+		{m.list.KeyMap.CursorUp, m.list.KeyMap.CursorDown, constants.Keymap.Enter},
+		{m.list.KeyMap.Filter, m.list.KeyMap.ClearFilter},
+		{constants.Keymap.Help, constants.Keymap.Quit},
+	}
+}
+
 func (m Model) UpdateWindowSize(w int, h int) tea.Model {
 	top, right, bottom, left := constants.DocStyle.GetMargin()
 	m.list.SetSize(w-left-right, h-top-bottom-1)
@@ -74,6 +93,7 @@ func menu() []list.Item {
 		item{title: "Generate Address", desc: ""},
 		item{title: "Build & Send Transaction", desc: ""},
 		item{title: "Keychain Management", desc: ""},
+		item{title: "History", desc: ""},
 	}
 	return menu
 }
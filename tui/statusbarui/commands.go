@@ -0,0 +1,27 @@
+package statusbarui
+
+import (
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fetchNodeInfoCmd probes the node which would be used for the endpoints
+func fetchNodeInfoCmd(id int, endpoints string) tea.Cmd {
+	return func() tea.Msg {
+		endpoint, err := tuiutils.SelectEndpoint(endpoints)
+		if err != nil {
+			// none of the nodes answers: describe the first one
+			endpoint = tuiutils.SplitEndpoints(endpoints)[0]
+		}
+		return nodeInfoMsg{id: id, info: tuiutils.GetNodeInfo(endpoint)}
+	}
+}
+
+// refreshCmd schedules the next probe of the node
+func refreshCmd(id int, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return refreshMsg{id: id}
+	})
+}
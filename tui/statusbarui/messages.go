@@ -0,0 +1,12 @@
+package statusbarui
+
+import "github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+
+type nodeInfoMsg struct {
+	id   int
+	info tuiutils.NodeInfo
+}
+
+type refreshMsg struct {
+	id int
+}
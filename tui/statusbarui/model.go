@@ -0,0 +1,132 @@
+package statusbarui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
+	"github.com/archethic-foundation/archethic-cli/tui/tuiutils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// refreshInterval is the delay between two probes of the node
+	refreshInterval = 30 * time.Second
+	// probeDelay avoids probing the node for each key typed in an url field
+	probeDelay = 500 * time.Millisecond
+)
+
+// networkStyle returns the style of the banner of the network in the current theme
+func networkStyle(network string) lipgloss.Style {
+	switch network {
+	case tuiutils.NetworkMainnet:
+		return constants.Theme.Mainnet
+	case tuiutils.NetworkTestnet:
+		return constants.Theme.Testnet
+	case tuiutils.NetworkLocal:
+		return constants.Theme.Local
+	default:
+		return constants.Theme.Custom
+	}
+}
+
+// Model the status bar displaying the state of the node selected in the active view
+type Model struct {
+	endpoints string
+	info      *tuiutils.NodeInfo
+	refreshID int
+	width     int
+}
+
+// New initialize the status bar
+func New() Model {
+	return Model{}
+}
+
+// SetEndpoints changes the endpoints described by the status bar and schedules the probe of the node used
+func (m Model) SetEndpoints(endpoints string) (Model, tea.Cmd) {
+	if endpoints == m.endpoints {
+		return m, nil
+	}
+	m.endpoints = endpoints
+	m.info = nil
+	m.refreshID++
+	if len(tuiutils.SplitEndpoints(endpoints)) == 0 {
+		return m, nil
+	}
+	return m, refreshCmd(m.refreshID, probeDelay)
+}
+
+// Update handle the results of the probes and the refresh ticks
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		_, right, _, left := constants.DocStyle.GetMargin()
+		m.width = msg.Width - left - right
+	case nodeInfoMsg:
+		if msg.id != m.refreshID {
+			return m, nil
+		}
+		m.info = &msg.info
+		return m, refreshCmd(m.refreshID, refreshInterval)
+	case refreshMsg:
+		if msg.id != m.refreshID {
+			return m, nil
+		}
+		return m, fetchNodeInfoCmd(m.refreshID, m.endpoints)
+	}
+	return m, nil
+}
+
+// Visible returns true when an endpoint is selected
+func (m Model) Visible() bool {
+	return len(tuiutils.SplitEndpoints(m.endpoints)) > 0
+}
+
+// View return the status bar, on a single line
+func (m Model) View() string {
+	if !m.Visible() {
+		return ""
+	}
+	endpoint := tuiutils.SplitEndpoints(m.endpoints)[0]
+	if m.info != nil {
+		endpoint = m.info.Endpoint
+	}
+	network := tuiutils.NetworkName(endpoint)
+	banner := networkStyle(network).Render(strings.ToUpper(network))
+
+	var details []string
+	details = append(details, endpoint)
+	switch {
+	case m.info == nil:
+		details = append(details, "probing...")
+	case !m.info.Reachable:
+		details = append(details, "unreachable")
+	default:
+		details = append(details, fmt.Sprintf("%d ms", m.info.LatencyMs))
+		if m.info.Version.Code != "" {
+			details = append(details, fmt.Sprintf("v%s (protocol %s)", m.info.Version.Code, m.info.Version.Protocol))
+		}
+		if m.info.OracleRates.Timestamp != 0 {
+			details = append(details, fmt.Sprintf("1 UCO = $%.4f / %.4f€", m.info.OracleRates.Usd, m.info.OracleRates.Eur))
+		}
+		if m.info.StorageNoncePublicKey != "" {
+			details = append(details, "storage nonce key "+abbreviate(m.info.StorageNoncePublicKey))
+		}
+	}
+	bar := lipgloss.JoinHorizontal(lipgloss.Top, banner, constants.Theme.StatusBar.Render(strings.Join(details, " · ")))
+	if m.width > 0 {
+		bar = lipgloss.NewStyle().MaxWidth(m.width).Render(bar)
+	}
+	return bar
+}
+
+// abbreviate keeps the beginning and the end of a key
+func abbreviate(key string) string {
+	if len(key) <= 16 {
+		return key
+	}
+	return key[:8] + "…" + key[len(key)-8:]
+}
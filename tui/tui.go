@@ -4,12 +4,20 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
+	"github.com/archethic-foundation/archethic-cli/logging"
+	"github.com/archethic-foundation/archethic-cli/tui/constants"
 	"github.com/archethic-foundation/archethic-cli/tui/generateaddressui"
+	"github.com/archethic-foundation/archethic-cli/tui/historyui"
 	"github.com/archethic-foundation/archethic-cli/tui/keychaincreatetransactionui"
 	"github.com/archethic-foundation/archethic-cli/tui/keychainmanagementui"
 	"github.com/archethic-foundation/archethic-cli/tui/mainui"
+	"github.com/archethic-foundation/archethic-cli/tui/statusbarui"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 var p *tea.Program
@@ -21,6 +29,7 @@ const (
 	generateAddressView
 	keychainManagementView
 	keychainCreateTransactionView
+	historyView
 	monthView
 	loadingView
 )
@@ -31,23 +40,38 @@ type MainModel struct {
 	generateAddress           tea.Model
 	keychainManagement        tea.Model
 	keychainCreateTransaction tea.Model
+	history                   tea.Model
+	statusBar                 statusbarui.Model
 	ActiveMenuID              uint
 	windowSize                tea.WindowSizeMsg
+	help                      help.Model
+	showHelp                  bool
+	// offset is the first line of the active view displayed, when the view is taller than the window
+	offset int
 }
 
+// endpointView is implemented by the views using a node, described in the status bar
+type endpointView interface {
+	Endpoints() string
+}
+
+// helpView is implemented by the views listing their key bindings in the help overlay
+type helpView interface {
+	Editing() bool
+	KeyMap() constants.HelpKeyMap
+}
+
+// statusBarHeight is the number of lines of the status bar
+const statusBarHeight = 1
+
+// scrollIndicatorHeight is the number of lines of the indicator displayed under a view taller than the window
+const scrollIndicatorHeight = 1
+
 // StartTea the entry point for the UI. Initializes the model.
 func StartTea(pvKeyBytes []byte) {
-	if f, err := tea.LogToFile("debug.log", "help"); err != nil {
-		fmt.Println("Couldn't open a file for logging:", err)
-		os.Exit(1)
-	} else {
-		defer func() {
-			err = f.Close()
-			if err != nil {
-				log.Fatal(err)
-			}
-		}()
-	}
+	// the standard logger, used by the libraries, would write over the views
+	log.SetFlags(0)
+	log.SetOutput(logging.Writer(logging.LevelDebug))
 
 	m := New(pvKeyBytes)
 	p = tea.NewProgram(m, tea.WithAltScreen())
@@ -65,6 +89,9 @@ func New(pvKeyBytes []byte) MainModel {
 		generateAddress:           generateaddressui.New(),
 		keychainManagement:        keychainmanagementui.New(pvKeyBytes),
 		keychainCreateTransaction: keychaincreatetransactionui.New(pvKeyBytes),
+		history:                   historyui.New(pvKeyBytes),
+		statusBar:                 statusbarui.New(),
+		help:                      help.New(),
 	}
 }
 
@@ -77,20 +104,67 @@ func (m MainModel) Init() tea.Cmd {
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
+	previousState := m.state
+	// the plain keys of the preset, such as h/j/k/l with vim, stand for other keys outside of the fields being edited
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !m.showHelp {
+		if view, ok := m.activeView().(helpView); ok && !view.Editing() {
+			msg = constants.TranslatePlainKey(keyMsg)
+		}
+	}
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.windowSize = msg
+		m.statusBar, _ = m.statusBar.Update(msg)
+		m.help.Width = msg.Width
 		// Update the sub views when the size of the window is changed
 		// It appears this is called during the init phase to get the inital
 		// window size.
-		m.generateAddress, _ = m.generateAddress.Update(msg)
-		m.main, _ = m.main.Update(msg)
+		// Every view is resized, not only the active one, so they are laid out when they are opened.
+		m.generateAddress, _ = m.generateAddress.Update(m.viewSize())
+		m.main, _ = m.main.Update(m.viewSize())
+		m.history, _ = m.history.Update(m.viewSize())
+		m.keychainManagement, _ = m.keychainManagement.Update(m.viewSize())
+		m.keychainCreateTransaction, _ = m.keychainCreateTransaction.Update(m.viewSize())
+		m.followFocus()
+		return m, nil
+	case tea.KeyMsg:
+		// while the help overlay is displayed, the keys are not forwarded to the views
+		if m.showHelp {
+			if key.Matches(msg, constants.Keymap.Quit) {
+				return m, tea.Quit
+			}
+			if key.Matches(msg, constants.Keymap.Help, constants.Keymap.Back) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		// the view is scrolled by hand, until the focus moves
+		if key.Matches(msg, constants.Keymap.ViewUp) {
+			m.offset--
+			m.clampOffset()
+			return m, nil
+		}
+		if key.Matches(msg, constants.Keymap.ViewDown) {
+			m.offset++
+			m.clampOffset()
+			return m, nil
+		}
+		if key.Matches(msg, constants.Keymap.Help) {
+			view, ok := m.activeView().(helpView)
+			// a printable key is typed in the field being edited instead of opening the help
+			if ok && (!view.Editing() || msg.Type != tea.KeyRunes) {
+				m.showHelp = true
+				return m, nil
+			}
+		}
 	case generateaddressui.BackMsg:
 		m.state = menuView
 	case keychainmanagementui.BackMsg:
 		m.state = menuView
 	case keychaincreatetransactionui.BackMsg:
 		m.state = menuView
+	case historyui.BackMsg:
+		m.state = menuView
 	case keychaincreatetransactionui.CreateTransactionMsg:
 		m.state = keychainCreateTransactionView
 	case mainui.SelectMsg:
@@ -101,9 +175,14 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = keychainCreateTransactionView
 		case 3:
 			m.state = keychainManagementView
+		case 4:
+			m.state = historyView
 		}
 	}
 
+	m.statusBar, cmd = m.statusBar.Update(msg)
+	cmds = append(cmds, cmd)
+
 	switch m.state {
 	case menuView:
 		newProject, newCmd := m.main.Update(msg)
@@ -142,26 +221,149 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !newModel.IsInit {
 			cmds = append(cmds, newModel.Init())
 			newModel.IsInit = true
+			// the view is recreated when leaving it
+			sizedModel, _ := newModel.Update(m.viewSize())
+			newModel = sizedModel.(keychaincreatetransactionui.Model)
 		}
 		m.keychainCreateTransaction = newModel
 		cmd = newCmd
+	case historyView:
+		newHistory, newCmd := m.history.Update(msg)
+		newModel, ok := newHistory.(historyui.Model)
+		if !ok {
+			panic("could not perform assertion on historyui model")
+		}
+		if !newModel.IsInit {
+			cmds = append(cmds, newModel.Init())
+			newModel.IsInit = true
+		}
+		m.history = newModel
+		cmd = newCmd
+	}
+	cmds = append(cmds, cmd)
+
+	// the status bar follows the endpoints of the active view
+	endpoints := ""
+	if view, ok := m.activeView().(endpointView); ok {
+		endpoints = view.Endpoints()
 	}
+	m.statusBar, cmd = m.statusBar.SetEndpoints(endpoints)
 	cmds = append(cmds, cmd)
+
+	if m.state != previousState {
+		m.offset = 0
+	}
+	m.followFocus()
 	return m, tea.Batch(cmds...)
 }
 
-// View return the text UI to be output to the terminal
-func (m MainModel) View() string {
+// viewSize returns the size available for the views, the last line being kept for the status bar
+func (m MainModel) viewSize() tea.WindowSizeMsg {
+	return tea.WindowSizeMsg{Width: m.windowSize.Width, Height: m.windowSize.Height - statusBarHeight}
+}
+
+// scrollHeight returns the number of lines of the active view displayed when it is taller than the window,
+// the last line being kept for the scroll indicator
+func (m MainModel) scrollHeight() int {
+	height := m.viewSize().Height - scrollIndicatorHeight
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+// followFocus scrolls the active view so the focused button or row, preceded by the focus marker, is displayed
+func (m *MainModel) followFocus() {
+	lines := strings.Split(m.activeView().View(), "\n")
+	height := m.scrollHeight()
+	if m.windowSize.Height == 0 || len(lines) <= m.viewSize().Height {
+		m.offset = 0
+		return
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, constants.Theme.FocusMarker) {
+			continue
+		}
+		if i < m.offset {
+			m.offset = i
+		} else if i >= m.offset+height {
+			m.offset = i - height + 1
+		}
+		break
+	}
+	m.clampOffset()
+}
+
+// clampOffset keeps the active view filling the window when it is scrolled
+func (m *MainModel) clampOffset() {
+	lines := strings.Count(m.activeView().View(), "\n") + 1
+	if maxOffset := lines - m.scrollHeight(); m.offset > maxOffset {
+		m.offset = maxOffset
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// scrolledView returns the lines of the view displayed in the window, followed by the scroll indicator
+// when the view is taller than the window
+func (m MainModel) scrolledView(view string) string {
+	lines := strings.Split(view, "\n")
+	if m.windowSize.Height == 0 || len(lines) <= m.viewSize().Height {
+		return view
+	}
+	height := m.scrollHeight()
+	offset := m.offset
+	if offset > len(lines)-height {
+		offset = len(lines) - height
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	indicator := constants.HelpStyle(fmt.Sprintf("lines %d-%d of %d - press '%s'/'%s' to scroll ", offset+1, offset+height, len(lines), constants.Keymap.ViewUp.Help().Key, constants.Keymap.ViewDown.Help().Key))
+	return strings.Join(lines[offset:offset+height], "\n") + "\n" + constants.DocStyle.Render(indicator)
+}
+
+// activeView returns the model of the current view
+func (m MainModel) activeView() tea.Model {
 	switch m.state {
-	case menuView:
-		return m.main.View()
 	case generateAddressView:
-		return m.generateAddress.View()
+		return m.generateAddress
 	case keychainManagementView:
-		return m.keychainManagement.View()
+		return m.keychainManagement
 	case keychainCreateTransactionView:
-		return m.keychainCreateTransaction.View()
+		return m.keychainCreateTransaction
+	case historyView:
+		return m.history
 	default:
-		return m.main.View()
+		return m.main
+	}
+}
+
+func (m MainModel) helpOverlayView() string {
+	view, ok := m.activeView().(helpView)
+	if !ok {
+		return ""
+	}
+	// the keys scrolling the views taller than the window are available in every view
+	bindings := append(view.KeyMap().FullHelp(), []key.Binding{constants.Keymap.ViewUp, constants.Keymap.ViewDown})
+	content := "Key bindings\n\n" + m.help.FullHelpView(bindings)
+	content += "\n\n" + constants.HelpStyle(fmt.Sprintf("press '%s' or '%s' to close ", constants.Keymap.Help.Help().Key, constants.Keymap.Back.Help().Key))
+	helpOverlayStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(constants.Theme.Border).
+		Padding(1, 4)
+	return constants.DocStyle.Render(helpOverlayStyle.Render(content))
+}
+
+// View return the text UI to be output to the terminal
+func (m MainModel) View() string {
+	if m.showHelp {
+		return m.helpOverlayView()
+	}
+	view := m.scrolledView(m.activeView().View())
+	if m.statusBar.Visible() {
+		view += "\n" + constants.DocStyle.Render(m.statusBar.View())
 	}
+	return view
 }
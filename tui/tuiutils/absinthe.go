@@ -0,0 +1,178 @@
+package tuiutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/nshafer/phx"
+)
+
+const (
+	absintheTopic = "__absinthe__:control"
+	replyTimeout  = 10 * time.Second
+)
+
+// absintheSocket is a client of the GraphQL subscriptions of a node, sent over its Phoenix websocket.
+// The Phoenix client reconnects and rejoins the control channel when the connection is lost. The subscriptions
+// being lost with the connection, Rejoined is signaled on each rejoin for them to be sent again.
+type absintheSocket struct {
+	socket   *phx.Socket
+	channel  *phx.Channel
+	lock     sync.Mutex
+	handlers map[string]func(data json.RawMessage)
+	joined   bool
+	rejoined chan struct{}
+}
+
+// dialAbsinthe connects to the websocket of the node and joins the Absinthe control channel
+func dialAbsinthe(endpoint string) (*absintheSocket, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = "/socket"
+
+	s := &absintheSocket{
+		socket:   phx.NewSocket(u),
+		handlers: map[string]func(data json.RawMessage){},
+		rejoined: make(chan struct{}, 1),
+	}
+	s.socket.OnMessage(s.dispatch)
+	err = s.socket.Connect()
+	if err != nil {
+		return nil, err
+	}
+	s.channel = s.socket.Channel(absintheTopic, nil)
+	s.channel.OnJoin(func(any) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if !s.joined {
+			s.joined = true
+			return
+		}
+		s.handlers = map[string]func(data json.RawMessage){}
+		select {
+		case s.rejoined <- struct{}{}:
+		default:
+		}
+	})
+	join, err := s.channel.Join()
+	if err == nil {
+		_, err = waitReply(join, "phx_join")
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Subscribe sends the GraphQL subscription, the handler receiving the data of each event
+func (s *absintheSocket) Subscribe(query string, variables map[string]interface{}, handler func(data json.RawMessage)) (string, error) {
+	response, err := s.push("doc", map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return "", err
+	}
+	var subscription struct {
+		SubscriptionID string `json:"subscriptionId"`
+	}
+	err = json.Unmarshal(response, &subscription)
+	if err != nil {
+		return "", err
+	}
+	s.lock.Lock()
+	s.handlers[subscription.SubscriptionID] = handler
+	s.lock.Unlock()
+	return subscription.SubscriptionID, nil
+}
+
+// Unsubscribe stops the subscription
+func (s *absintheSocket) Unsubscribe(subscriptionID string) error {
+	s.lock.Lock()
+	delete(s.handlers, subscriptionID)
+	s.lock.Unlock()
+	_, err := s.push("unsubscribe", map[string]interface{}{"subscriptionId": subscriptionID})
+	return err
+}
+
+// Rejoined receives a value when the connection was lost and the control channel joined again
+func (s *absintheSocket) Rejoined() <-chan struct{} {
+	return s.rejoined
+}
+
+func (s *absintheSocket) Close() error {
+	return s.socket.Disconnect()
+}
+
+// push sends an event to the control channel and waits for its reply
+func (s *absintheSocket) push(event string, payload interface{}) (json.RawMessage, error) {
+	push, err := s.channel.Push(event, payload)
+	if err != nil {
+		return nil, err
+	}
+	return waitReply(push, event)
+}
+
+// waitReply waits for the reply of the node to the push, and returns its response encoded in JSON
+func waitReply(push *phx.Push, event string) (json.RawMessage, error) {
+	type reply struct {
+		status   string
+		response any
+	}
+	// the callbacks of a join are called again on each rejoin, once the reply was read
+	replies := make(chan reply, 1)
+	for _, status := range []string{"ok", "error", "timeout"} {
+		status := status
+		push.Receive(status, func(response any) {
+			select {
+			case replies <- reply{status: status, response: response}:
+			default:
+			}
+		})
+	}
+	select {
+	case r := <-replies:
+		response, err := json.Marshal(r.response)
+		if err != nil {
+			return nil, err
+		}
+		switch r.status {
+		case "ok":
+			return response, nil
+		case "timeout":
+			return nil, fmt.Errorf("no reply of the node to %s", event)
+		default:
+			return nil, fmt.Errorf("%s refused by the node: %s", event, response)
+		}
+	case <-time.After(replyTimeout):
+		return nil, fmt.Errorf("no reply of the node to %s", event)
+	}
+}
+
+// dispatch sends the data of the subscriptions' events to their handler
+func (s *absintheSocket) dispatch(message phx.Message) {
+	if message.Event != "subscription:data" {
+		return
+	}
+	s.lock.Lock()
+	handler, ok := s.handlers[message.Topic]
+	s.lock.Unlock()
+	if !ok {
+		return
+	}
+	payload, err := json.Marshal(message.Payload)
+	if err != nil {
+		return
+	}
+	var data struct {
+		Result struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"result"`
+	}
+	if json.Unmarshal(payload, &data) == nil {
+		handler(data.Result.Data)
+	}
+}
@@ -0,0 +1,143 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/config"
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+const (
+	// ServiceKeyPrefix references the public key of a keychain's service: service:NAME
+	ServiceKeyPrefix = "service:"
+	// ContactKeyPrefix references the public key of a contact of the configuration file: @NAME
+	ContactKeyPrefix = "@"
+)
+
+// AuthorizedKeyCandidate is a public key which can be authorized in an ownership, with the reference describing its origin
+type AuthorizedKeyCandidate struct {
+	Reference string
+	PublicKey string
+}
+
+// KeyResolver resolves the references to public keys: the services of the keychain of the access seed and the contacts.
+// The keychain is fetched once, when a service is referenced for the first time.
+type KeyResolver struct {
+	Endpoint   string
+	AccessSeed []byte
+	Contacts   map[string]config.Contact
+	keychain   *archethic.Keychain
+}
+
+// Resolve returns the hex encoded public key referenced by service:NAME (the key of the service at the current index of its chain),
+// by @NAME (the public key of a contact), or the public key itself
+func (r *KeyResolver) Resolve(reference string) (string, error) {
+	switch {
+	case strings.HasPrefix(reference, ServiceKeyPrefix):
+		return r.servicePublicKey(strings.TrimPrefix(reference, ServiceKeyPrefix))
+	case strings.HasPrefix(reference, ContactKeyPrefix):
+		name := strings.TrimPrefix(reference, ContactKeyPrefix)
+		contact, ok := r.Contacts[name]
+		if !ok {
+			return "", fmt.Errorf("unknown contact %s", name)
+		}
+		if contact.PublicKey == "" {
+			return "", fmt.Errorf("the contact %s has no public key", name)
+		}
+		return checkPublicKeyHex(contact.PublicKey)
+	default:
+		return checkPublicKeyHex(reference)
+	}
+}
+
+// Candidates returns the public keys of the keychain's services (when the access seed is a keychain's one) and of the contacts
+func (r *KeyResolver) Candidates() ([]AuthorizedKeyCandidate, error) {
+	var candidates []AuthorizedKeyCandidate
+	var err error
+	if len(r.AccessSeed) > 0 {
+		err = r.loadKeychain()
+	}
+	if r.keychain != nil {
+		names := make([]string, 0, len(r.keychain.Services))
+		for name := range r.keychain.Services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			publicKey, err := r.servicePublicKey(name)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, AuthorizedKeyCandidate{Reference: ServiceKeyPrefix + name, PublicKey: publicKey})
+		}
+	}
+
+	names := make([]string, 0, len(r.Contacts))
+	for name, contact := range r.Contacts {
+		if contact.PublicKey != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		publicKey, err := checkPublicKeyHex(r.Contacts[name].PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of the contact %s: %w", name, err)
+		}
+		candidates = append(candidates, AuthorizedKeyCandidate{Reference: ContactKeyPrefix + name, PublicKey: publicKey})
+	}
+	// the keychain is optional: the error is only returned when there is no candidate at all
+	if len(candidates) == 0 && err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+func (r *KeyResolver) loadKeychain() error {
+	if r.keychain != nil {
+		return nil
+	}
+	if len(r.AccessSeed) == 0 {
+		return errors.New("the access seed of the keychain is required to use the key of a service")
+	}
+	keychain, err := getKeychain(r.Endpoint, r.AccessSeed)
+	if err != nil {
+		return err
+	}
+	r.keychain = keychain
+	return nil
+}
+
+func (r *KeyResolver) servicePublicKey(name string) (string, error) {
+	err := r.loadKeychain()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := r.keychain.Services[name]; !ok {
+		return "", errors.New("service " + name + " not found in the keychain")
+	}
+	genesisAddress, err := r.keychain.DeriveAddress(name, 0)
+	if err != nil {
+		return "", err
+	}
+	index, err := GetLastTransactionIndexOfAddress(r.Endpoint, hex.EncodeToString(genesisAddress))
+	if err != nil {
+		return "", err
+	}
+	publicKey, _, err := r.keychain.DeriveKeypair(name, uint8(index))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(publicKey)), nil
+}
+
+func checkPublicKeyHex(publicKey string) (string, error) {
+	if _, err := hex.DecodeString(publicKey); err != nil || publicKey == "" {
+		return "", fmt.Errorf("invalid public key %s", publicKey)
+	}
+	return strings.ToUpper(publicKey), nil
+}
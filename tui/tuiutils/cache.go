@@ -0,0 +1,91 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// cachedKeychain lists the services of a keychain, so they can be completed without the access seed
+type cachedKeychain struct {
+	Endpoint  string   `json:"endpoint"`
+	Services  []string `json:"services"`
+	UpdatedAt int64    `json:"updated_at"`
+}
+
+// servicesCachePath returns the location of the cache of the keychains' services: <user cache dir>/archethic-cli/services.json
+func servicesCachePath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "archethic-cli", "services.json")
+}
+
+// readServicesCache returns the cached keychains by the address of their genesis transaction
+func readServicesCache(path string) map[string]cachedKeychain {
+	cache := map[string]cachedKeychain{}
+	cacheBytes, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	json.Unmarshal(cacheBytes, &cache)
+	return cache
+}
+
+// cacheKeychainServices records the names of the services of the keychain. Only the names are cached, never the seed.
+// The cache is a convenience for the shell completion: a failure to write it is only logged.
+func cacheKeychainServices(endpoint string, keychain *archethic.Keychain) {
+	path := servicesCachePath()
+	if path == "" || keychain == nil {
+		return
+	}
+	address, err := archethic.DeriveAddress(keychain.Seed, 0, archethic.ED25519, archethic.SHA256)
+	if err != nil {
+		return
+	}
+	services := make([]string, 0, len(keychain.Services))
+	for name := range keychain.Services {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	cache := readServicesCache(path)
+	cache[hex.EncodeToString(address)] = cachedKeychain{Endpoint: endpoint, Services: services, UpdatedAt: time.Now().Unix()}
+	cacheBytes, err := json.MarshalIndent(cache, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, cacheBytes, 0600)
+	}
+	if err != nil {
+		logging.Warn("cannot cache the services of the keychain", "endpoint", endpoint, "error", err)
+	}
+}
+
+// CachedServiceNames returns the names of the services of the keychains accessed previously
+func CachedServiceNames() []string {
+	path := servicesCachePath()
+	if path == "" {
+		return nil
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, keychain := range readServicesCache(path) {
+		for _, name := range keychain.Services {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,100 @@
+package tuiutils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentEncoding is the transformation applied to the content of a transaction before it is sent
+type ContentEncoding string
+
+const (
+	RawContent        ContentEncoding = "raw"
+	GzipContent       ContentEncoding = "gzip"
+	Base64Content     ContentEncoding = "base64"
+	GzipBase64Content ContentEncoding = "gzip+base64"
+)
+
+// ContentEncodings lists the content encodings in the order they are cycled through in the TUI
+var ContentEncodings = []ContentEncoding{RawContent, GzipContent, Base64Content, GzipBase64Content}
+
+// ParseContentEncoding returns the content encoding with the given name, raw when the name is empty
+func ParseContentEncoding(name string) (ContentEncoding, error) {
+	if name == "" {
+		return RawContent, nil
+	}
+	for _, encoding := range ContentEncodings {
+		if string(encoding) == name {
+			return encoding, nil
+		}
+	}
+	return RawContent, fmt.Errorf("invalid content encoding %s, expecting raw, gzip, base64 or gzip+base64", name)
+}
+
+// EncodeContent compresses and/or encodes the content in base64
+func EncodeContent(content []byte, encoding ContentEncoding) ([]byte, error) {
+	switch encoding {
+	case RawContent, "":
+		return content, nil
+	case GzipContent:
+		return gzipContent(content)
+	case Base64Content:
+		return []byte(base64.StdEncoding.EncodeToString(content)), nil
+	case GzipBase64Content:
+		compressed, err := gzipContent(content)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(base64.StdEncoding.EncodeToString(compressed)), nil
+	}
+	return nil, fmt.Errorf("invalid content encoding %s", encoding)
+}
+
+func gzipContent(content []byte) ([]byte, error) {
+	var b bytes.Buffer
+	writer := gzip.NewWriter(&b)
+	_, err := writer.Write(content)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// IsBinaryContent returns true when the content is not printable text (invalid UTF-8 or NUL bytes)
+func IsBinaryContent(content []byte) bool {
+	return !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0
+}
+
+// FormatSize renders a number of bytes with a binary unit
+func FormatSize(size int) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KiB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1024*1024))
+	}
+}
+
+// HexPreview renders the first bytes of the content as an hexadecimal dump, 16 bytes per line
+func HexPreview(content []byte, maxBytes int) string {
+	preview := content
+	if len(preview) > maxBytes {
+		preview = preview[:maxBytes]
+	}
+	dump := strings.TrimSuffix(hex.Dump(preview), "\n")
+	if len(content) > maxBytes {
+		dump += fmt.Sprintf("\n... %d more bytes", len(content)-maxBytes)
+	}
+	return dump
+}
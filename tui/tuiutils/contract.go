@@ -0,0 +1,226 @@
+package tuiutils
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// ContractTokenKind is the syntactic category of a token of a smart contract
+type ContractTokenKind int
+
+const (
+	TokenText ContractTokenKind = iota
+	TokenKeyword
+	TokenKey
+	TokenModule
+	TokenAtom
+	TokenString
+	TokenNumber
+	TokenComment
+	TokenBracket
+)
+
+// ContractToken is a piece of a line of a smart contract
+type ContractToken struct {
+	Kind ContractTokenKind
+	Text string
+}
+
+// ContractIssue is a structural problem of a smart contract, at a line starting at 1
+type ContractIssue struct {
+	Line    int
+	Message string
+}
+
+func (i ContractIssue) String() string {
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+var contractKeywords = map[string]bool{
+	"condition": true, "actions": true, "triggered_by": true,
+	"if": true, "else": true, "export": true, "fun": true,
+	"true": true, "false": true, "nil": true, "and": true, "or": true, "not": true, "in": true,
+	"transaction": true, "oracle": true, "interval": true, "datetime": true, "inherit": true,
+}
+
+var closingBrackets = map[string]string{")": "(", "]": "[", "}": "{", "end": "do"}
+
+// TokenizeContract splits each line of the smart contract into tokens.
+// The strings spanning several lines are kept as string tokens on each line.
+func TokenizeContract(code string) [][]ContractToken {
+	var lines [][]ContractToken
+	var line []ContractToken
+	runes := []rune(code)
+	inString := false
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case r == '\n':
+			lines = append(lines, line)
+			line = nil
+			i++
+			continue
+		case inString || r == '"':
+			if !inString {
+				i++
+			}
+			inString = true
+			for i < len(runes) && runes[i] != '\n' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					inString = false
+					i++
+					break
+				}
+				i++
+			}
+			line = append(line, ContractToken{Kind: TokenString, Text: string(runes[start:i])})
+			continue
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			line = append(line, ContractToken{Kind: TokenComment, Text: string(runes[start:i])})
+			continue
+		case r == ':' && i+1 < len(runes) && isIdentifierStart(runes[i+1]):
+			i++
+			for i < len(runes) && isIdentifierPart(runes[i]) {
+				i++
+			}
+			line = append(line, ContractToken{Kind: TokenAtom, Text: string(runes[start:i])})
+			continue
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '_') {
+				i++
+			}
+			line = append(line, ContractToken{Kind: TokenNumber, Text: string(runes[start:i])})
+			continue
+		case isIdentifierStart(r):
+			for i < len(runes) && isIdentifierPart(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := TokenText
+			switch {
+			// keyword list key (but not a module function call such as String.to_number)
+			case i < len(runes) && runes[i] == ':' && (i+1 >= len(runes) || runes[i+1] != ':'):
+				i++
+				word += ":"
+				kind = TokenKey
+			case word == "do" || word == "end" || word == "fn":
+				kind = TokenBracket
+			case contractKeywords[word]:
+				kind = TokenKeyword
+			case unicode.IsUpper(r):
+				kind = TokenModule
+			}
+			line = append(line, ContractToken{Kind: kind, Text: word})
+			continue
+		case r == '(' || r == ')' || r == '[' || r == ']' || r == '{' || r == '}':
+			i++
+			line = append(line, ContractToken{Kind: TokenBracket, Text: string(r)})
+			continue
+		}
+
+		// plain text until the next significant character
+		i++
+		for i < len(runes) && !isSignificant(runes, i) {
+			i++
+		}
+		line = append(line, ContractToken{Kind: TokenText, Text: string(runes[start:i])})
+	}
+	return append(lines, line)
+}
+
+func isIdentifierStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentifierPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '?' || r == '!'
+}
+
+func isSignificant(runes []rune, i int) bool {
+	r := runes[i]
+	switch r {
+	case '\n', '"', '#', '(', ')', '[', ']', '{', '}':
+		return true
+	case ':':
+		return i+1 < len(runes) && isIdentifierStart(runes[i+1])
+	}
+	return isIdentifierStart(r) || unicode.IsDigit(r)
+}
+
+// CheckContractBalance reports the unbalanced brackets and do/end blocks and the unterminated strings of the smart contract
+func CheckContractBalance(code string) []ContractIssue {
+	type opener struct {
+		text string
+		line int
+	}
+	var issues []ContractIssue
+	var stack []opener
+
+	for i, tokens := range TokenizeContract(code) {
+		lineNumber := i + 1
+		for _, token := range tokens {
+			if token.Kind != TokenBracket {
+				continue
+			}
+			switch token.Text {
+			case "(", "[", "{", "do", "fn":
+				stack = append(stack, opener{text: token.Text, line: lineNumber})
+			default:
+				if len(stack) == 0 {
+					issues = append(issues, ContractIssue{Line: lineNumber, Message: fmt.Sprintf("unexpected '%s'", token.Text)})
+					continue
+				}
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if top.text != closingBrackets[token.Text] && !(token.Text == "end" && top.text == "fn") {
+					issues = append(issues, ContractIssue{Line: lineNumber, Message: fmt.Sprintf("'%s' closes the '%s' opened at line %d", token.Text, top.text, top.line)})
+				}
+			}
+		}
+	}
+	if line := unterminatedStringLine(code); line != 0 {
+		issues = append(issues, ContractIssue{Line: line, Message: "unterminated string"})
+	}
+	for _, open := range stack {
+		if open.text == "do" || open.text == "fn" {
+			issues = append(issues, ContractIssue{Line: open.line, Message: fmt.Sprintf("'%s' has no matching 'end'", open.text)})
+		} else {
+			issues = append(issues, ContractIssue{Line: open.line, Message: fmt.Sprintf("'%s' is never closed", open.text)})
+		}
+	}
+	return issues
+}
+
+// unterminatedStringLine returns the line of the string which is not closed, 0 when all the strings are closed
+func unterminatedStringLine(code string) int {
+	line, stringLine := 1, 0
+	inComment := false
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; {
+		case c == '\n':
+			line++
+			inComment = false
+		case inComment:
+		case c == '\\' && stringLine != 0:
+			i++
+		case c == '"':
+			if stringLine == 0 {
+				stringLine = line
+			} else {
+				stringLine = 0
+			}
+		case c == '#' && stringLine == 0:
+			inComment = true
+		}
+	}
+	return stringLine
+}
@@ -0,0 +1,250 @@
+package tuiutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+	archethic "github.com/archethic-foundation/libgo"
+	"github.com/hasura/go-graphql-client"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+// retry policy of the idempotent queries
+const (
+	maxAttempts       = 3
+	baseRetryDelay    = 200 * time.Millisecond
+	maxRetryDelay     = 2 * time.Second
+	probeTimeout      = 5 * time.Second
+	healthTTL         = time.Minute
+	endpointsSplitter = ","
+)
+
+// endpointState is the result of the last health probe of a node, valid for healthTTL
+type endpointState struct {
+	healthy   bool
+	checkedAt time.Time
+}
+
+var endpointHealth = struct {
+	sync.Mutex
+	states map[string]endpointState
+}{states: map[string]endpointState{}}
+
+// SplitEndpoints returns the endpoints of a comma separated list
+func SplitEndpoints(endpoints string) []string {
+	var list []string
+	for _, endpoint := range strings.Split(endpoints, endpointsSplitter) {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint != "" {
+			list = append(list, strings.TrimSuffix(endpoint, "/"))
+		}
+	}
+	return list
+}
+
+// ValidateEndpoint checks the endpoint is an absolute http(s) URL
+func ValidateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %s: %w", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid endpoint %s: expecting an http or https URL", endpoint)
+	}
+	return nil
+}
+
+// SelectEndpoint returns the first healthy endpoint of the comma separated list.
+// A single endpoint is returned without being probed.
+func SelectEndpoint(endpoints string) (string, error) {
+	list := SplitEndpoints(endpoints)
+	switch len(list) {
+	case 0:
+		return "", errors.New("no endpoint provided")
+	case 1:
+		logging.Info("using node", "endpoint", list[0])
+		return list[0], nil
+	}
+	for _, endpoint := range list {
+		if isHealthy(endpoint) {
+			logging.Info("using node", "endpoint", endpoint)
+			return endpoint, nil
+		}
+	}
+	return "", fmt.Errorf("no healthy node among %s", strings.Join(list, ", "))
+}
+
+// WithEndpoint runs an idempotent query against the endpoints of the comma separated list, in order:
+// the query is retried with an exponential backoff on each healthy endpoint, and the next one is used when it keeps failing
+func WithEndpoint(endpoints string, query func(endpoint string) error) error {
+	list := SplitEndpoints(endpoints)
+	if len(list) == 0 {
+		return errors.New("no endpoint provided")
+	}
+	var err error
+	for _, endpoint := range list {
+		if len(list) > 1 && !isHealthy(endpoint) {
+			err = fmt.Errorf("node %s is not reachable", endpoint)
+			continue
+		}
+		err = Retry(func() error {
+			return query(endpoint)
+		})
+		if err == nil {
+			logging.Debug("used node", "endpoint", endpoint)
+			return nil
+		}
+		// the node still answers: the error comes from the query itself
+		if !IsRetryable(err) || probeEndpoint(endpoint) == nil {
+			return err
+		}
+		logging.Warn("node failed, trying the next one", "endpoint", endpoint, "error", err)
+		markUnhealthy(endpoint)
+	}
+	return err
+}
+
+// Retry runs the operation until it succeeds, returns an error which is not retryable or the attempts are exhausted
+func Retry(operation func() error) error {
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := retryDelay(attempt)
+			logging.Debug("attempt failed, retrying", "attempt", attempt, "delay", delay, "error", err)
+			time.Sleep(delay)
+		}
+		err = operation()
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// retryDelay returns the exponential backoff delay of the attempt, with a random jitter of up to half of the delay
+func retryDelay(attempt int) time.Duration {
+	delay := baseRetryDelay << (attempt - 1)
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// IsRetryable returns true for the transport errors only: the node could not be reached, did not answer in time
+// or answered with a server error (5xx). The other errors, returned by the node or by libgo (e.g. a keychain not found),
+// would be returned again.
+func IsRetryable(err error) bool {
+	var netError net.Error
+	if errors.As(err, &netError) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var graphqlErrors graphql.Errors
+	if errors.As(err, &graphqlErrors) {
+		// the GraphQL client reports the failed requests with the request_error code,
+		// and the error statuses as "<status>; body: <body>"
+		if len(graphqlErrors) == 0 || graphqlErrors[0].Extensions["code"] != graphql.ErrRequestError {
+			return false
+		}
+		status, isStatus := responseStatus(graphqlErrors[0].Message)
+		return !isStatus || status >= 500
+	}
+	var httpError *jsonrpc.HTTPError
+	if errors.As(err, &httpError) {
+		return httpError.Code >= 500
+	}
+	return false
+}
+
+// responseStatus returns the HTTP status code starting the message of a GraphQL request error, if any
+func responseStatus(message string) (int, bool) {
+	code, _, found := strings.Cut(message, " ")
+	if !found || len(code) != 3 {
+		return 0, false
+	}
+	status, err := strconv.Atoi(code)
+	return status, err == nil
+}
+
+// isHealthy returns the health of the endpoint, probing it when it was not probed in the last healthTTL
+func isHealthy(endpoint string) bool {
+	endpointHealth.Lock()
+	state, probed := endpointHealth.states[endpoint]
+	endpointHealth.Unlock()
+	if probed && time.Since(state.checkedAt) < healthTTL {
+		return state.healthy
+	}
+	healthy := probeEndpoint(endpoint) == nil
+	setHealth(endpoint, healthy)
+	return healthy
+}
+
+func markUnhealthy(endpoint string) {
+	setHealth(endpoint, false)
+}
+
+func setHealth(endpoint string, healthy bool) {
+	endpointHealth.Lock()
+	defer endpointHealth.Unlock()
+	endpointHealth.states[endpoint] = endpointState{healthy: healthy, checkedAt: time.Now()}
+}
+
+// probeEndpoint checks the node answers to a minimal GraphQL query
+func probeEndpoint(endpoint string) error {
+	err := queryNode(endpoint, "{ __typename }", nil, nil, probeTimeout)
+	if err != nil {
+		logging.Info("node is unhealthy", "endpoint", endpoint, "error", err)
+	}
+	return err
+}
+
+// GetStorageNoncePublicKey fetches the storage nonce public key of the network, used to authorize the nodes in ownerships
+func GetStorageNoncePublicKey(endpoints string) (string, error) {
+	var publicKey string
+	err := WithEndpoint(endpoints, func(endpoint string) error {
+		var err error
+		publicKey, err = archethic.NewAPIClient(endpoint).GetStorageNoncePublicKey()
+		return err
+	})
+	return publicKey, err
+}
+
+// GetLastTransactionIndexOfAddress returns the index of the next transaction of the chain of the address (0 if the chain does not exist)
+func GetLastTransactionIndexOfAddress(endpoints string, address string) (int, error) {
+	var result struct {
+		LastTransaction struct {
+			ChainLength int `json:"chainLength"`
+		} `json:"lastTransaction"`
+	}
+	err := QueryNode(endpoints, "query($address: Address!) { lastTransaction(address: $address) { chainLength } }", map[string]interface{}{"address": address}, &result)
+	if errors.Is(err, ErrChainNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.LastTransaction.ChainLength, nil
+}
+
+// getKeychain fetches the keychain of the access seed, retrying on network errors
+func getKeychain(endpoints string, seed []byte) (*archethic.Keychain, error) {
+	logging.AddSecretBytes(seed)
+	var keychain *archethic.Keychain
+	err := WithEndpoint(endpoints, func(endpoint string) error {
+		var err error
+		keychain, err = archethic.GetKeychain(seed, *archethic.NewAPIClient(endpoint))
+		if err == nil {
+			cacheKeychainServices(endpoint, keychain)
+		}
+		return err
+	})
+	return keychain, err
+}
@@ -0,0 +1,229 @@
+package tuiutils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+)
+
+var (
+	versionFormat   = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+	diffVersion     = regexp.MustCompile(`(?m)^\+\s*(?:@version\s+|version:\s*)"(\d+\.\d+\.\d+)"`)
+	descriptionPart = regexp.MustCompile(`(?s)Description: (.*?)\nChanges:`)
+	changesPart     = regexp.MustCompile(`(?s)Changes:\n?(.*)`)
+)
+
+// Proposal is a code proposal: the unified diff of the node's code, which sets its new version
+type Proposal struct {
+	Address     string `json:"address"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Changes     string `json:"changes,omitempty"`
+	Approvals   int    `json:"approvals"`
+	Open        bool   `json:"open"`
+}
+
+// BuildProposalContent builds the content of a code proposal transaction, in the format parsed by the nodes.
+// The diff must set the version of the node (in mix.exs) to the given version.
+func BuildProposalContent(description string, version string, diff string) (string, error) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return "", errors.New("the description of the proposal is required")
+	}
+	if !versionFormat.MatchString(version) {
+		return "", fmt.Errorf("invalid version %s, expecting MAJOR.MINOR.PATCH", version)
+	}
+	diff = strings.TrimSpace(diff)
+	if !strings.HasPrefix(diff, "diff --git") {
+		return "", errors.New("the changes must be a unified diff generated by git diff")
+	}
+	match := diffVersion.FindStringSubmatch(diff)
+	if match == nil {
+		return "", fmt.Errorf("the diff doesn't set the version of the node to %s", version)
+	}
+	if match[1] != version {
+		return "", fmt.Errorf("the diff sets the version of the node to %s instead of %s", match[1], version)
+	}
+	return fmt.Sprintf("Description: %s\nChanges:\n%s\n", description, diff), nil
+}
+
+// ParseProposal extracts the description, the changes and the version of a code proposal transaction's content
+func ParseProposal(address string, content string) (Proposal, error) {
+	description := descriptionPart.FindStringSubmatch(content)
+	changes := changesPart.FindStringSubmatch(content)
+	if description == nil || changes == nil {
+		return Proposal{}, fmt.Errorf("transaction %s is not a valid code proposal", address)
+	}
+	proposal := Proposal{
+		Address:     address,
+		Description: strings.TrimSpace(description[1]),
+		Changes:     strings.TrimSpace(changes[1]),
+	}
+	if version := diffVersion.FindStringSubmatch(proposal.Changes); version != nil {
+		proposal.Version = version[1]
+	}
+	return proposal, nil
+}
+
+// GetProposal fetches and parses the code proposal at the given address
+func GetProposal(endpoint string, address string) (Proposal, error) {
+	transaction, err := GetTransaction(endpoint, address)
+	if err != nil {
+		return Proposal{}, err
+	}
+	if transaction.Type != "code_proposal" {
+		return Proposal{}, fmt.Errorf("transaction %s is a %s transaction, not a code proposal", address, transaction.Type)
+	}
+	return ParseProposal(transaction.Address, transaction.Data.Content)
+}
+
+// maxNetworkPages is the number of pages of network transactions fetched at most
+const maxNetworkPages = 20
+
+// ProposalSummary is a code proposal as listed, with its version but without its description and its changes
+type ProposalSummary struct {
+	Address   string `json:"address"`
+	Timestamp int64  `json:"timestamp"`
+	Version   string `json:"version"`
+	Approvals int    `json:"approvals"`
+	Open      bool   `json:"open"`
+}
+
+// networkTransactionGQL holds the fields of the network transactions which are listed
+type networkTransactionGQL struct {
+	Address string `json:"address"`
+	Data    struct {
+		Content    string   `json:"content"`
+		Recipients []string `json:"recipients"`
+	} `json:"data"`
+	ValidationStamp struct {
+		Timestamp int64 `json:"timestamp"`
+	} `json:"validationStamp"`
+}
+
+// getNetworkTransactions fetches the given fields of the network transactions of the given type, page by page,
+// up to maxNetworkPages pages
+func getNetworkTransactions(endpoint string, transactionType string, fields string) ([]networkTransactionGQL, error) {
+	var transactions []networkTransactionGQL
+	query := fmt.Sprintf("query($type: String!, $page: Int) { networkTransactions(type: $type, page: $page) { %s } }", fields)
+	for page := 1; page <= maxNetworkPages; page++ {
+		var result struct {
+			NetworkTransactions []networkTransactionGQL `json:"networkTransactions"`
+		}
+		err := QueryNode(endpoint, query, map[string]interface{}{"type": transactionType, "page": page}, &result)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.NetworkTransactions) == 0 {
+			return transactions, nil
+		}
+		transactions = append(transactions, result.NetworkTransactions...)
+	}
+	logging.Warn("too many network transactions, only the first pages are listed", "type", transactionType, "pages", maxNetworkPages)
+	return transactions, nil
+}
+
+// ListProposals fetches the code proposals of the network with their number of approvals and their status,
+// only the open proposals being returned unless all is set
+func ListProposals(endpoint string, all bool) ([]ProposalSummary, error) {
+	proposalTransactions, err := getNetworkTransactions(endpoint, "code_proposal", "address validationStamp { timestamp } data { content }")
+	if err != nil {
+		return nil, err
+	}
+	approvals, err := getApprovals(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	nodeVersion, err := getNodeVersion(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	proposals := make([]ProposalSummary, 0, len(proposalTransactions))
+	for _, transaction := range proposalTransactions {
+		proposal := ProposalSummary{
+			Address:   transaction.Address,
+			Timestamp: transaction.ValidationStamp.Timestamp,
+			Approvals: approvals[strings.ToUpper(transaction.Address)],
+		}
+		if version := diffVersion.FindStringSubmatch(transaction.Data.Content); version != nil {
+			proposal.Version = version[1]
+		}
+		proposal.Open = compareVersions(proposal.Version, nodeVersion) > 0
+		if proposal.Open || all {
+			proposals = append(proposals, proposal)
+		}
+	}
+	return proposals, nil
+}
+
+// OpenProposal fetches the code proposal with its content, its number of approvals and its status.
+// A proposal is open while its version is greater than the version of the node.
+func OpenProposal(endpoint string, address string) (Proposal, error) {
+	proposal, err := GetProposal(endpoint, address)
+	if err != nil {
+		return Proposal{}, err
+	}
+	approvals, err := getApprovals(endpoint)
+	if err != nil {
+		return Proposal{}, err
+	}
+	nodeVersion, err := getNodeVersion(endpoint)
+	if err != nil {
+		return Proposal{}, err
+	}
+	proposal.Approvals = approvals[strings.ToUpper(proposal.Address)]
+	proposal.Open = compareVersions(proposal.Version, nodeVersion) > 0
+	return proposal, nil
+}
+
+// getNodeVersion returns the version of the node's code, the proposals of greater versions being open
+func getNodeVersion(endpoint string) (string, error) {
+	var nodeVersion struct {
+		Version NodeVersion `json:"version"`
+	}
+	err := QueryNode(endpoint, "{ version { code } }", nil, &nodeVersion)
+	if err != nil {
+		return "", err
+	}
+	return nodeVersion.Version.Code, nil
+}
+
+// getApprovals returns the number of approvals of each proposal, by address in upper case
+func getApprovals(endpoint string) (map[string]int, error) {
+	approvalTransactions, err := getNetworkTransactions(endpoint, "code_approval", "data { recipients }")
+	if err != nil {
+		return nil, err
+	}
+	approvals := map[string]int{}
+	for _, approval := range approvalTransactions {
+		for _, recipient := range approval.Data.Recipients {
+			approvals[strings.ToUpper(recipient)]++
+		}
+	}
+	return approvals, nil
+}
+
+// compareVersions compares two MAJOR.MINOR.PATCH versions, an invalid version being the lowest
+func compareVersions(a string, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < 3; i++ {
+		var numberA, numberB int
+		if i < len(partsA) {
+			numberA, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			numberB, _ = strconv.Atoi(partsB[i])
+		}
+		if numberA != numberB {
+			if numberA > numberB {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
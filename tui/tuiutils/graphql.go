@@ -0,0 +1,150 @@
+package tuiutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+	"github.com/hasura/go-graphql-client"
+)
+
+const queryTimeout = 30 * time.Second
+
+// ErrChainNotFound is returned by the queries on the last transaction of a chain which does not exist
+var ErrChainNotFound = errors.New("the chain does not exist")
+
+type TransactionGQL struct {
+	Address           string `json:"address"`
+	Type              string `json:"type"`
+	Version           int    `json:"version"`
+	PreviousPublicKey string `json:"previousPublicKey"`
+	Data              struct {
+		Content string `json:"content"`
+		Code    string `json:"code"`
+		Ledger  struct {
+			Uco struct {
+				Transfers []struct {
+					To     string `json:"to"`
+					Amount uint64 `json:"amount"`
+				} `json:"transfers"`
+			} `json:"uco"`
+			Token struct {
+				Transfers []struct {
+					To           string `json:"to"`
+					Amount       uint64 `json:"amount"`
+					TokenAddress string `json:"tokenAddress"`
+					TokenId      int    `json:"tokenId"`
+				} `json:"transfers"`
+			} `json:"token"`
+		} `json:"ledger"`
+		Ownerships []OwnershipGQL `json:"ownerships"`
+		Recipients []string       `json:"recipients"`
+	} `json:"data"`
+	ValidationStamp struct {
+		Timestamp int64 `json:"timestamp"`
+	} `json:"validationStamp"`
+}
+
+type OwnershipGQL struct {
+	Secret               string `json:"secret"`
+	AuthorizedPublicKeys []struct {
+		PublicKey          string `json:"publicKey"`
+		EncryptedSecretKey string `json:"encryptedSecretKey"`
+	} `json:"authorizedPublicKeys"`
+}
+
+const transactionFieldsGQL = `
+	address
+	type
+	version
+	previousPublicKey
+	data {
+		content
+		code
+		ledger {
+			uco { transfers { to amount } }
+			token { transfers { to amount tokenAddress tokenId } }
+		}
+		ownerships {
+			secret
+			authorizedPublicKeys { publicKey encryptedSecretKey }
+		}
+		recipients
+	}
+	validationStamp { timestamp }
+`
+
+// QueryNode sends a GraphQL query to the node's API and decodes the returned data into result.
+// The query is retried, and sent to the next node when several comma separated endpoints are given.
+// The errors are the ones of the GraphQL client used by libgo (graphql.Errors).
+func QueryNode(endpoints string, query string, variables map[string]interface{}, result interface{}) error {
+	return WithEndpoint(endpoints, func(endpoint string) error {
+		return queryNode(endpoint, query, variables, result, queryTimeout)
+	})
+}
+
+func queryNode(endpoint string, query string, variables map[string]interface{}, result interface{}, timeout time.Duration) error {
+	client := graphql.NewClient(strings.TrimSuffix(endpoint, "/")+"/api", &http.Client{Timeout: timeout})
+	start := time.Now()
+	data, err := client.ExecRaw(context.Background(), query, variables)
+	logging.Debug("node call", "endpoint", endpoint, "duration", time.Since(start), "error", err)
+	if err != nil {
+		return nodeError(err)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// nodeError wraps the errors of the node into ErrChainNotFound when the queried chain does not exist
+func nodeError(err error) error {
+	var graphqlErrors graphql.Errors
+	if errors.As(err, &graphqlErrors) {
+		for _, graphqlError := range graphqlErrors {
+			if strings.Contains(graphqlError.Message, "not_exists") {
+				return fmt.Errorf("%w: %w", ErrChainNotFound, err)
+			}
+		}
+	}
+	return err
+}
+
+// GetTransaction fetches the transaction at the given address
+func GetTransaction(endpoint string, address string) (*TransactionGQL, error) {
+	var result struct {
+		Transaction *TransactionGQL `json:"transaction"`
+	}
+	query := fmt.Sprintf("query($address: Address!) { transaction(address: $address) { %s } }", transactionFieldsGQL)
+	err := QueryNode(endpoint, query, map[string]interface{}{"address": address}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Transaction == nil {
+		return nil, fmt.Errorf("transaction %s not found", address)
+	}
+	return result.Transaction, nil
+}
+
+// GetTransactionChain fetches a page of the transaction chain of the address, starting after the paging address when given
+func GetTransactionChain(endpoint string, address string, pagingAddress string) ([]TransactionGQL, error) {
+	var result struct {
+		TransactionChain []TransactionGQL `json:"transactionChain"`
+	}
+	variables := map[string]interface{}{"address": address}
+	query := fmt.Sprintf("query($address: Address!) { transactionChain(address: $address) { %s } }", transactionFieldsGQL)
+	if pagingAddress != "" {
+		variables["pagingAddress"] = pagingAddress
+		query = fmt.Sprintf("query($address: Address!, $pagingAddress: Address) { transactionChain(address: $address, pagingAddress: $pagingAddress) { %s } }", transactionFieldsGQL)
+	}
+	err := QueryNode(endpoint, query, variables, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.TransactionChain, nil
+}
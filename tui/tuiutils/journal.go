@@ -0,0 +1,270 @@
+package tuiutils
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// EnvJournalPath is the environment variable used to override the location of the journal of the sent transactions
+const EnvJournalPath = "ARCHETHIC_CLI_JOURNAL"
+
+// JournalCommand is the command recorded in the journal for the transactions sent, set by the command run
+var JournalCommand = "tui"
+
+// JournalEntry records a transaction sent by the CLI or the TUI, without any secret: neither the seeds,
+// nor the content or the ownerships of the transaction. An update entry records the status of the transaction
+// confirmed or rejected by the node after the entry of its sending was written.
+type JournalEntry struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Operator  string            `json:"operator"`
+	Host      string            `json:"host"`
+	Command   string            `json:"command"`
+	Endpoint  string            `json:"endpoint"`
+	Address   string            `json:"address"`
+	Type      string            `json:"type"`
+	Transfers []JournalTransfer `json:"transfers,omitempty"`
+	Fee       uint64            `json:"fee"`
+	Status    string            `json:"status"`
+	Error     string            `json:"error,omitempty"`
+	Update    bool              `json:"update,omitempty"`
+}
+
+// JournalTransfer is a transfer of UCO, when the token address is empty, or of token
+type JournalTransfer struct {
+	To           string `json:"to"`
+	Amount       uint64 `json:"amount"`
+	TokenAddress string `json:"token_address,omitempty"`
+	TokenId      int    `json:"token_id,omitempty"`
+}
+
+// JournalPath returns the location of the journal: $ARCHETHIC_CLI_JOURNAL or <user config dir>/archethic-cli/journal.jsonl.
+// The journal is kept next to the configuration rather than in the cache, as it is an audit trail.
+func JournalPath() string {
+	if path := os.Getenv(EnvJournalPath); path != "" {
+		return path
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "archethic-cli", "journal.jsonl")
+}
+
+// appendJournal appends the entry to the journal, a JSON object per line. The journal is locked while
+// the line is written, so the lines written by simultaneous processes are not interleaved.
+func appendJournal(entry JournalEntry) error {
+	path := JournalPath()
+	if path == "" {
+		return errors.New("no configuration directory for the journal")
+	}
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	journal, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer journal.Close()
+	err = lockFile(journal)
+	if err != nil {
+		return err
+	}
+	defer unlockFile(journal)
+	_, err = journal.Write(append(entryBytes, '\n'))
+	return err
+}
+
+// ReadJournal returns the entries of the journal, the oldest first, the updates of the status being applied
+// to the entry of the transaction they update. A missing journal has no entry.
+func ReadJournal() ([]JournalEntry, error) {
+	entries, err := readJournalEntries()
+	if err != nil {
+		return nil, err
+	}
+	var merged []JournalEntry
+	for _, entry := range entries {
+		if entry.Update {
+			for i := len(merged) - 1; i >= 0; i-- {
+				if merged[i].Address == entry.Address {
+					merged[i].Status = entry.Status
+					merged[i].Error = entry.Error
+					break
+				}
+			}
+			continue
+		}
+		merged = append(merged, entry)
+	}
+	return merged, nil
+}
+
+// readJournalEntries returns the lines of the journal, the updates of the status included
+func readJournalEntries() ([]JournalEntry, error) {
+	path := JournalPath()
+	if path == "" {
+		return nil, errors.New("no configuration directory for the journal")
+	}
+	journal, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer journal.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(journal)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry JournalEntry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry at line %d of %s: %w", line, path, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// FindJournalEntries returns the entries of the transaction, one per attempt to send it and per update of its status
+func FindJournalEntries(address string) ([]JournalEntry, error) {
+	entries, err := readJournalEntries()
+	if err != nil {
+		return nil, err
+	}
+	var found []JournalEntry
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Address, address) {
+			found = append(found, entry)
+		}
+	}
+	return found, nil
+}
+
+// trackJournal records the transaction in the journal: the returned function appends its entry once its sending is
+// finished, with the status tracked in the session. When the node confirms or rejects the transaction afterwards,
+// an entry updating its status is appended, as the reservations of the pending transactions are released.
+func trackJournal(ts *archethic.TransactionSender, transaction *archethic.TransactionBuilder, endpoint string, fee uint64) func() {
+	var lock sync.Mutex
+	var entry *JournalEntry
+	update := func(status string, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		// before the entry is written, the status is the one of the session
+		if entry == nil || entry.Status == StatusConfirmed || entry.Status == StatusFailed {
+			return
+		}
+		entry.Timestamp = time.Now().UTC()
+		entry.Status = status
+		entry.Error = ""
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		entry.Update = true
+		writeJournalEntry(*entry)
+	}
+	ts.AddOnRequiredConfirmation(func(nbConf int) {
+		update(StatusConfirmed, nil)
+	})
+	ts.AddOnError(func(senderContext string, message error) {
+		update(StatusFailed, handleTransactionError(message))
+	})
+	return func() {
+		lock.Lock()
+		defer lock.Unlock()
+		sent := newJournalEntry(transaction, endpoint, fee)
+		writeJournalEntry(sent)
+		entry = &sent
+	}
+}
+
+// newJournalEntry builds the entry of the transaction, with the status tracked in the session
+func newJournalEntry(transaction *archethic.TransactionBuilder, endpoint string, fee uint64) JournalEntry {
+	address := strings.ToUpper(hex.EncodeToString(transaction.Address))
+	entry := JournalEntry{
+		Timestamp: time.Now().UTC(),
+		Operator:  journalOperator(),
+		Command:   JournalCommand,
+		Endpoint:  endpoint,
+		Address:   address,
+		Type:      GetTransactionTypeName(transaction.TxType),
+		Fee:       fee,
+		Status:    StatusPending,
+	}
+	entry.Host, _ = os.Hostname()
+	if sent, ok := sentTransaction(address); ok {
+		entry.Status = sent.Status
+		entry.Error = sent.Error
+	}
+	for _, transfer := range transaction.Data.Ledger.Uco.Transfers {
+		entry.Transfers = append(entry.Transfers, JournalTransfer{
+			To:     strings.ToUpper(hex.EncodeToString(transfer.To)),
+			Amount: transfer.Amount,
+		})
+	}
+	for _, transfer := range transaction.Data.Ledger.Token.Transfers {
+		entry.Transfers = append(entry.Transfers, JournalTransfer{
+			To:           strings.ToUpper(hex.EncodeToString(transfer.To)),
+			Amount:       transfer.Amount,
+			TokenAddress: strings.ToUpper(hex.EncodeToString(transfer.TokenAddress)),
+			TokenId:      transfer.TokenId,
+		})
+	}
+	return entry
+}
+
+// writeJournalEntry appends the entry to the journal. The journal must not prevent sending transactions:
+// a failure to write it is only logged.
+func writeJournalEntry(entry JournalEntry) {
+	err := appendJournal(entry)
+	if err != nil {
+		logging.Warn("cannot write the journal", "address", entry.Address, "error", err)
+	}
+}
+
+// journalFee returns the fee of the transaction recorded in the journal: the fee already estimated before sending
+// when given, otherwise the fee estimated by the node, or 0 if it can't be estimated
+func journalFee(client *archethic.APIClient, transaction *archethic.TransactionBuilder, estimated *archethic.Fee) uint64 {
+	if estimated != nil {
+		return uint64(estimated.Fee)
+	}
+	fee, err := client.GetTransactionFee(transaction)
+	if err != nil {
+		logging.Warn("cannot estimate the fee of the transaction", "address", transaction.Address, "error", err)
+		return 0
+	}
+	return uint64(fee.Fee)
+}
+
+// journalOperator returns the name of the user of the machine sending the transaction
+func journalOperator() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
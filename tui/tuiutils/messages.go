@@ -0,0 +1,236 @@
+package tuiutils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// messageProtocol identifies the content of the transactions carrying a message
+const messageProtocol = "archethic-cli/message/1"
+
+// messageEnvelope is the content of a message transaction: the message encrypted with the key shared by the ownership
+type messageEnvelope struct {
+	Protocol string `json:"protocol"`
+	Cipher   []byte `json:"cipher"`
+}
+
+// messagePayload is the encrypted part of the message, so the thread is not disclosed either
+type messagePayload struct {
+	Text    string `json:"text"`
+	ReplyTo string `json:"reply_to,omitempty"`
+}
+
+// Message is a decrypted message, sent by us or addressed to us, with the replies to it
+type Message struct {
+	Address   string     `json:"address"`
+	To        string     `json:"to"`
+	ReplyTo   string     `json:"reply_to,omitempty"`
+	Text      string     `json:"text"`
+	Timestamp int64      `json:"timestamp"`
+	Sent      bool       `json:"sent"`
+	Replies   []*Message `json:"replies,omitempty"`
+}
+
+// BuildMessageTransaction builds a transfer transaction addressed to the recipient, whose content is the message
+// encrypted with a random key. The key is shared by an ownership with the recipient's public key and the sender's one.
+func BuildMessageTransaction(text string, replyTo string, to []byte, amount uint64, recipientPublicKey []byte, senderPublicKey []byte, secretKey []byte) (*archethic.TransactionBuilder, error) {
+	payload, err := json.Marshal(messagePayload{Text: text, ReplyTo: strings.ToUpper(replyTo)})
+	if err != nil {
+		return nil, err
+	}
+	messageKey := make([]byte, 32)
+	rand.Read(messageKey)
+	cipher, err := archethic.AesEncrypt(payload, messageKey)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(messageEnvelope{Protocol: messageProtocol, Cipher: cipher})
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys := []string{hex.EncodeToString(recipientPublicKey)}
+	if !strings.EqualFold(publicKeys[0], hex.EncodeToString(senderPublicKey)) {
+		publicKeys = append(publicKeys, hex.EncodeToString(senderPublicKey))
+	}
+	ownershipCipher, authorizedKeys, err := EncryptOwnership(messageKey, publicKeys, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := archethic.NewTransaction(archethic.TransferType)
+	transaction.SetContent(content)
+	transaction.AddOwnership(ownershipCipher, authorizedKeys)
+	transaction.AddUcoTransfer(to, amount)
+	return transaction, nil
+}
+
+// GetChainPublicKey returns the last public key of the chain of the address, the one of its last transaction
+func GetChainPublicKey(endpoint string, address string) ([]byte, error) {
+	var result struct {
+		LastTransaction struct {
+			PreviousPublicKey string `json:"previousPublicKey"`
+		} `json:"lastTransaction"`
+	}
+	err := QueryNode(endpoint, "query($address: Address!) { lastTransaction(address: $address) { previousPublicKey } }", map[string]interface{}{"address": address}, &result)
+	if errors.Is(err, ErrChainNotFound) {
+		return nil, fmt.Errorf("the chain of %s has no transaction, its public key is unknown", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(result.LastTransaction.PreviousPublicKey)
+}
+
+// ReadMessages decrypts the messages sent by the chain and the ones received in its inputs, and returns them as threads
+// sorted by date: the messages which are not a reply to a known message, each one with its replies.
+func ReadMessages(endpoint string, seed []byte, curve archethic.Curve, serviceName string) ([]*Message, error) {
+	keypairs, err := CandidateKeypairs(endpoint, seed, curve, serviceName, -1)
+	if err != nil {
+		return nil, err
+	}
+	genesisAddress, err := GenesisAddress(endpoint, seed, curve, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	genesis := strings.ToUpper(hex.EncodeToString(genesisAddress))
+	chain, err := getFullTransactionChain(endpoint, genesis)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*Message
+	addresses := []string{genesis}
+	for i := range chain {
+		addresses = append(addresses, chain[i].Address)
+		message, ok := decryptMessage(&chain[i], keypairs)
+		if ok {
+			message.Sent = true
+			messages = append(messages, message)
+		}
+	}
+
+	// the senders of the transfers received by any address of the chain, except the chain itself
+	seen := map[string]bool{}
+	for _, address := range addresses {
+		seen[strings.ToUpper(address)] = true
+	}
+	for _, address := range addresses {
+		inputs, err := getInputs(endpoint, address)
+		if err != nil {
+			return nil, err
+		}
+		for _, input := range inputs {
+			from := strings.ToUpper(input.From)
+			if input.Type == "call" || seen[from] {
+				continue
+			}
+			seen[from] = true
+			transaction, err := GetTransaction(endpoint, from)
+			if err != nil {
+				return nil, err
+			}
+			if message, ok := decryptMessage(transaction, keypairs); ok {
+				messages = append(messages, message)
+			}
+		}
+	}
+	return threadMessages(messages), nil
+}
+
+// decryptMessage returns the message carried by the transaction, if it is one and one of our keys can decrypt it
+func decryptMessage(transaction *TransactionGQL, keypairs []Keypair) (*Message, bool) {
+	var envelope messageEnvelope
+	if json.Unmarshal([]byte(transaction.Data.Content), &envelope) != nil || envelope.Protocol != messageProtocol {
+		return nil, false
+	}
+	for _, ownership := range transaction.Data.Ownerships {
+		secret, found, err := DecryptOwnership(ownership, keypairs)
+		if err != nil || !found {
+			continue
+		}
+		payloadBytes, err := archethic.AesDecrypt(envelope.Cipher, secret.Secret)
+		if err != nil {
+			continue
+		}
+		var payload messagePayload
+		if json.Unmarshal(payloadBytes, &payload) != nil {
+			continue
+		}
+		message := &Message{
+			Address:   strings.ToUpper(transaction.Address),
+			ReplyTo:   payload.ReplyTo,
+			Text:      payload.Text,
+			Timestamp: transaction.ValidationStamp.Timestamp,
+		}
+		if len(transaction.Data.Ledger.Uco.Transfers) > 0 {
+			message.To = strings.ToUpper(transaction.Data.Ledger.Uco.Transfers[0].To)
+		}
+		return message, true
+	}
+	return nil, false
+}
+
+// threadMessages attaches the replies to their message, the messages being sorted by date.
+// The replies forming a cycle are threaded from the oldest message of the cycle.
+func threadMessages(messages []*Message) []*Message {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp < messages[j].Timestamp
+	})
+	byAddress := map[string]*Message{}
+	order := map[*Message]int{}
+	for i, message := range messages {
+		byAddress[message.Address] = message
+		order[message] = i
+	}
+	var threads []*Message
+	for _, message := range messages {
+		parent, ok := byAddress[message.ReplyTo]
+		if message.ReplyTo != "" && ok && !isCycleRoot(message, byAddress, order) {
+			parent.Replies = append(parent.Replies, message)
+		} else {
+			threads = append(threads, message)
+		}
+	}
+	return threads
+}
+
+// isCycleRoot returns true when the message replies, directly or not, to itself and is the oldest message of the cycle
+func isCycleRoot(message *Message, byAddress map[string]*Message, order map[*Message]int) bool {
+	visited := map[*Message]bool{}
+	oldest := message
+	for current := byAddress[message.ReplyTo]; current != nil && !visited[current]; current = byAddress[current.ReplyTo] {
+		if current == message {
+			return oldest == message
+		}
+		visited[current] = true
+		if order[current] < order[oldest] {
+			oldest = current
+		}
+	}
+	return false
+}
+
+// getFullTransactionChain fetches all the pages of the transaction chain
+func getFullTransactionChain(endpoint string, address string) ([]TransactionGQL, error) {
+	var transactions []TransactionGQL
+	pagingAddress := ""
+	for {
+		page, err := GetTransactionChain(endpoint, address, pagingAddress)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return transactions, nil
+		}
+		transactions = append(transactions, page...)
+		pagingAddress = page[len(page)-1].Address
+	}
+}
@@ -0,0 +1,106 @@
+package tuiutils
+
+import (
+	"net/url"
+	"time"
+)
+
+// Network names of the endpoints
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+	NetworkLocal   = "local"
+	NetworkCustom  = "custom"
+)
+
+var knownNetworks = map[string]string{
+	"https://mainnet.archethic.net": NetworkMainnet,
+	"https://testnet.archethic.net": NetworkTestnet,
+	"http://localhost:4000":         NetworkLocal,
+}
+
+// NodeVersion is the version of the node's software and of the protocols it uses
+type NodeVersion struct {
+	Code        string `json:"code"`
+	Protocol    string `json:"protocol"`
+	Transaction int    `json:"transaction"`
+}
+
+// OracleRates is the last UCO price published by the oracle of the network
+type OracleRates struct {
+	Timestamp int64   `json:"timestamp"`
+	Eur       float64 `json:"eur"`
+	Usd       float64 `json:"usd"`
+}
+
+// NodeInfo describes the state of a node and of its network
+type NodeInfo struct {
+	Endpoint              string      `json:"endpoint"`
+	Network               string      `json:"network"`
+	Reachable             bool        `json:"reachable"`
+	LatencyMs             int64       `json:"latencyMs"`
+	Version               NodeVersion `json:"version"`
+	StorageNoncePublicKey string      `json:"storageNoncePublicKey"`
+	OracleRates           OracleRates `json:"oracleRates"`
+	Error                 string      `json:"error,omitempty"`
+}
+
+// NetworkName returns the network of the endpoint: mainnet, testnet, local or custom
+func NetworkName(endpoint string) string {
+	if network, ok := knownNetworks[endpoint]; ok {
+		return network
+	}
+	u, err := url.Parse(endpoint)
+	if err == nil {
+		switch u.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+			return NetworkLocal
+		}
+	}
+	return NetworkCustom
+}
+
+// GetNodeInfo probes the node and fetches its version, the storage nonce public key and the oracle rates.
+// The failures are reported in the Error field, the node being unreachable when the probe fails.
+func GetNodeInfo(endpoint string) NodeInfo {
+	info := NodeInfo{Endpoint: endpoint, Network: NetworkName(endpoint)}
+
+	start := time.Now()
+	err := queryNode(endpoint, "{ __typename }", nil, nil, probeTimeout)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Reachable = true
+	info.LatencyMs = time.Since(start).Milliseconds()
+
+	var result struct {
+		Version    NodeVersion `json:"version"`
+		OracleData struct {
+			Timestamp int64 `json:"timestamp"`
+			Services  struct {
+				Uco struct {
+					Eur float64 `json:"eur"`
+					Usd float64 `json:"usd"`
+				} `json:"uco"`
+			} `json:"services"`
+		} `json:"oracleData"`
+	}
+	err = QueryNode(endpoint, "{ version { code protocol transaction } oracleData { timestamp services { uco { eur usd } } } }", nil, &result)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Version = result.Version
+	info.OracleRates = OracleRates{
+		Timestamp: result.OracleData.Timestamp,
+		Eur:       result.OracleData.Services.Uco.Eur,
+		Usd:       result.OracleData.Services.Uco.Usd,
+	}
+
+	info.StorageNoncePublicKey, err = GetStorageNoncePublicKey(endpoint)
+	if err != nil {
+		info.Error = err.Error()
+	}
+	return info
+}
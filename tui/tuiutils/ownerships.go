@@ -0,0 +1,204 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+type Keypair struct {
+	PublicKey  []byte
+	PrivateKey []byte
+}
+
+// DecryptedSecret is the secret of an ownership decrypted with one of our keys
+type DecryptedSecret struct {
+	OwnershipIndex int
+	PublicKey      string
+	Secret         []byte
+	SecretKey      []byte
+	// public keys authorized by the ownership, hex encoded
+	AuthorizedKeys []string
+}
+
+// CandidateKeypairs returns the keypairs which may have been authorized in an ownership: the keypair of the given index,
+// or, if the index is negative, all the keypairs of the chain up to its last index.
+// When a service name is provided, the keypairs are derived from the keychain's service instead of the seed.
+func CandidateKeypairs(endpoint string, seed []byte, curve archethic.Curve, serviceName string, index int) ([]Keypair, error) {
+	if serviceName != "" {
+		keychain, err := getKeychain(endpoint, seed)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := keychain.Services[serviceName]; !ok {
+			return nil, errors.New("service " + serviceName + " not found in the keychain")
+		}
+		from, to := index, index
+		if index < 0 {
+			genesisAddress, err := keychain.DeriveAddress(serviceName, 0)
+			if err != nil {
+				return nil, err
+			}
+			lastIndex, err := GetLastTransactionIndexOfAddress(endpoint, hex.EncodeToString(genesisAddress))
+			if err != nil {
+				return nil, err
+			}
+			from, to = 0, lastIndex
+		}
+		keypairs := make([]Keypair, 0, to-from+1)
+		for i := from; i <= to; i++ {
+			publicKey, privateKey, err := keychain.DeriveKeypair(serviceName, uint8(i))
+			if err != nil {
+				return nil, err
+			}
+			keypairs = append(keypairs, Keypair{PublicKey: publicKey, PrivateKey: privateKey})
+		}
+		return keypairs, nil
+	}
+
+	from, to := index, index
+	if index < 0 {
+		lastIndex, err := GetLastTransactionIndex(endpoint, curve, seed)
+		if err != nil {
+			return nil, err
+		}
+		from, to = 0, lastIndex
+	}
+	keypairs := make([]Keypair, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		publicKey, privateKey, err := archethic.DeriveKeypair(seed, uint32(i), curve)
+		if err != nil {
+			return nil, err
+		}
+		keypairs = append(keypairs, Keypair{PublicKey: publicKey, PrivateKey: privateKey})
+	}
+	return keypairs, nil
+}
+
+// DecryptOwnerships decrypts the secrets of the ownerships for which one of the keypairs is authorized
+func DecryptOwnerships(ownerships []OwnershipGQL, keypairs []Keypair) ([]DecryptedSecret, error) {
+	var secrets []DecryptedSecret
+	for i, ownership := range ownerships {
+		decrypted, found, err := DecryptOwnership(ownership, keypairs)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			decrypted.OwnershipIndex = i
+			secrets = append(secrets, decrypted)
+		}
+	}
+	if len(secrets) == 0 {
+		return nil, errors.New("none of our keys is authorized to decrypt the ownerships of this transaction")
+	}
+	return secrets, nil
+}
+
+// DecryptOwnership decrypts the ownership's secret if one of the keypairs is authorized
+func DecryptOwnership(ownership OwnershipGQL, keypairs []Keypair) (DecryptedSecret, bool, error) {
+	for _, authorizedKey := range ownership.AuthorizedPublicKeys {
+		for _, keypair := range keypairs {
+			if !strings.EqualFold(authorizedKey.PublicKey, hex.EncodeToString(keypair.PublicKey)) {
+				continue
+			}
+			encryptedSecretKey, err := hex.DecodeString(authorizedKey.EncryptedSecretKey)
+			if err != nil {
+				return DecryptedSecret{}, false, err
+			}
+			secretKey, err := archethic.EcDecrypt(encryptedSecretKey, keypair.PrivateKey)
+			if err != nil {
+				return DecryptedSecret{}, false, err
+			}
+			cipher, err := hex.DecodeString(ownership.Secret)
+			if err != nil {
+				return DecryptedSecret{}, false, err
+			}
+			secret, err := archethic.AesDecrypt(cipher, secretKey)
+			if err != nil {
+				return DecryptedSecret{}, false, err
+			}
+			logging.AddSecretBytes(secretKey)
+			logging.AddSecret(string(secret))
+			logging.AddSecretBytes(secret)
+			authorizedKeys := make([]string, len(ownership.AuthorizedPublicKeys))
+			for i, key := range ownership.AuthorizedPublicKeys {
+				authorizedKeys[i] = strings.ToUpper(key.PublicKey)
+			}
+			return DecryptedSecret{
+				PublicKey:      strings.ToUpper(authorizedKey.PublicKey),
+				Secret:         secret,
+				SecretKey:      secretKey,
+				AuthorizedKeys: authorizedKeys,
+			}, true, nil
+		}
+	}
+	return DecryptedSecret{}, false, nil
+}
+
+// ReadSecrets fetches the ownerships of the transaction and decrypts the secrets we are authorized to read
+func ReadSecrets(endpoint string, transactionAddress string, keypairs []Keypair) ([]DecryptedSecret, error) {
+	transaction, err := GetTransaction(endpoint, transactionAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(transaction.Data.Ownerships) == 0 {
+		return nil, errors.New("the transaction has no ownership")
+	}
+	return DecryptOwnerships(transaction.Data.Ownerships, keypairs)
+}
+
+// UpdateAuthorizedKeys returns the authorized public keys with the added keys and without the removed ones.
+// The keys are hex encoded and compared case-insensitively.
+func UpdateAuthorizedKeys(current []string, add []string, remove []string) []string {
+	removed := map[string]bool{}
+	for _, key := range remove {
+		removed[strings.ToUpper(key)] = true
+	}
+	seen := map[string]bool{}
+	var keys []string
+	for _, key := range current {
+		key = strings.ToUpper(key)
+		if !removed[key] && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range add {
+		key = strings.ToUpper(key)
+		if !removed[key] && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// EncryptOwnership encrypts the secret with the secret key, and the secret key with each authorized public key
+func EncryptOwnership(secret []byte, publicKeys []string, secretKey []byte) ([]byte, []archethic.AuthorizedKey, error) {
+	logging.AddSecret(string(secret))
+	logging.AddSecretBytes(secret)
+	cipher, err := archethic.AesEncrypt(secret, secretKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	authorizedKeys := make([]archethic.AuthorizedKey, len(publicKeys))
+	for i, key := range publicKeys {
+		keyByte, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid authorization key %s", key)
+		}
+		encrypedSecretKey, err := archethic.EcEncrypt(secretKey, keyByte)
+		if err != nil {
+			return nil, nil, err
+		}
+		authorizedKeys[i] = archethic.AuthorizedKey{
+			PublicKey:          keyByte,
+			EncryptedSecretKey: encrypedSecretKey,
+		}
+	}
+	return cipher, authorizedKeys, nil
+}
@@ -0,0 +1,134 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// PaymentURIScheme is the scheme of the payment request URIs, modelled on BIP 21:
+// archethic:<address>?amount=<UCO or token amount>&token=<token address>&token_id=<id>&memo=<text>
+const PaymentURIScheme = "archethic"
+
+// PaymentRequest is a request to transfer UCO, when the token address is empty, or tokens to an address
+type PaymentRequest struct {
+	Address string `json:"address"`
+	// Amount is in the smallest unit (10^-8), 0 when the payer chooses the amount
+	Amount       uint64 `json:"amount,omitempty"`
+	TokenAddress string `json:"token_address,omitempty"`
+	TokenId      int    `json:"token_id,omitempty"`
+	// Memo is displayed to the payer, it is not written in the transaction
+	Memo string `json:"memo,omitempty"`
+}
+
+// URI encodes the payment request, the amount being a decimal amount
+func (r PaymentRequest) URI() string {
+	query := url.Values{}
+	if r.Amount > 0 {
+		query.Set("amount", FormatAmount(r.Amount))
+	}
+	if r.TokenAddress != "" {
+		query.Set("token", r.TokenAddress)
+		query.Set("token_id", strconv.Itoa(r.TokenId))
+	}
+	if r.Memo != "" {
+		query.Set("memo", r.Memo)
+	}
+	uri := PaymentURIScheme + ":" + r.Address
+	if len(query) > 0 {
+		// the spaces of the memo are encoded as %20, as some wallets don't decode the + of the query strings
+		uri += "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+	}
+	return uri
+}
+
+// Validate checks the addresses are hexadecimal
+func (r PaymentRequest) Validate() error {
+	if err := checkHexAddress(r.Address); err != nil {
+		return fmt.Errorf("invalid address of the payment request: %w", err)
+	}
+	if r.TokenAddress != "" {
+		if err := checkHexAddress(r.TokenAddress); err != nil {
+			return fmt.Errorf("invalid token address of the payment request: %w", err)
+		}
+	}
+	if r.TokenId < 0 {
+		return errors.New("the token id of the payment request cannot be negative")
+	}
+	return nil
+}
+
+// IsPaymentURI returns true when the text looks like a payment request URI, such as a pasted one
+func IsPaymentURI(text string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(text)), PaymentURIScheme+":")
+}
+
+// ParsePaymentRequest decodes a payment request URI. The parameters prefixed by req- are required by the payee:
+// the request is rejected when one of them is unknown.
+func ParsePaymentRequest(uri string) (PaymentRequest, error) {
+	uri = strings.TrimSpace(uri)
+	if !IsPaymentURI(uri) {
+		return PaymentRequest{}, fmt.Errorf("the payment request must start with %s:", PaymentURIScheme)
+	}
+	target, rawQuery, _ := strings.Cut(uri[len(PaymentURIScheme)+1:], "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return PaymentRequest{}, fmt.Errorf("invalid parameters of the payment request: %w", err)
+	}
+	request := PaymentRequest{
+		Address:      strings.ToUpper(strings.TrimPrefix(target, "//")),
+		TokenAddress: strings.ToUpper(query.Get("token")),
+		Memo:         query.Get("memo"),
+	}
+	if amount := query.Get("amount"); amount != "" {
+		request.Amount, err = ParseAmount(amount)
+		if err != nil {
+			return PaymentRequest{}, fmt.Errorf("invalid amount of the payment request: %w", err)
+		}
+	}
+	if tokenId := query.Get("token_id"); tokenId != "" {
+		request.TokenId, err = strconv.Atoi(tokenId)
+		if err != nil {
+			return PaymentRequest{}, fmt.Errorf("invalid token id of the payment request: %s", tokenId)
+		}
+	}
+	for name := range query {
+		switch name {
+		case "amount", "token", "token_id", "memo":
+		default:
+			if strings.HasPrefix(name, "req-") {
+				return PaymentRequest{}, fmt.Errorf("the payment request requires the unsupported parameter %s", name)
+			}
+		}
+	}
+	return request, request.Validate()
+}
+
+// PaymentQRCode renders the text as a QR code made of half blocks, two rows of modules per line.
+// The modules are drawn for a dark background, unless lightBackground is set.
+func PaymentQRCode(text string, lightBackground bool) (string, error) {
+	code, err := qrcode.New(text, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	return code.ToSmallString(lightBackground), nil
+}
+
+func checkHexAddress(address string) error {
+	if address == "" {
+		return errors.New("no address")
+	}
+	bytes, err := hex.DecodeString(address)
+	if err != nil {
+		return fmt.Errorf("%s is not hexadecimal", address)
+	}
+	if len(bytes) < 2 {
+		return fmt.Errorf("%s is too short", address)
+	}
+	return nil
+}
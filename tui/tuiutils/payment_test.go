@@ -0,0 +1,183 @@
+package tuiutils
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	testPaymentAddress = "0000A1B2C3D4E5F60718293A4B5C6D7E8F90A1B2C3D4E5F60718293A4B5C6D7E8F90"
+	testTokenAddress   = "00001122334455667788990011223344556677889900112233445566778899001122"
+)
+
+func TestPaymentRequestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		request PaymentRequest
+		uri     string
+	}{
+		{
+			name:    "address only",
+			request: PaymentRequest{Address: testPaymentAddress},
+			uri:     "archethic:" + testPaymentAddress,
+		},
+		{
+			name:    "UCO amount and memo with spaces",
+			request: PaymentRequest{Address: testPaymentAddress, Amount: 1_250_000_000, Memo: "Order 42 & co"},
+			uri:     "archethic:" + testPaymentAddress + "?amount=12.5&memo=Order%2042%20%26%20co",
+		},
+		{
+			name:    "token and token id",
+			request: PaymentRequest{Address: testPaymentAddress, Amount: 1, TokenAddress: testTokenAddress, TokenId: 3},
+			uri:     "archethic:" + testPaymentAddress + "?amount=0.00000001&token=" + testTokenAddress + "&token_id=3",
+		},
+		{
+			name:    "token id 0",
+			request: PaymentRequest{Address: testPaymentAddress, TokenAddress: testTokenAddress},
+			uri:     "archethic:" + testPaymentAddress + "?token=" + testTokenAddress + "&token_id=0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uri := test.request.URI()
+			if uri != test.uri {
+				t.Errorf("URI() = %s, want %s", uri, test.uri)
+			}
+			request, err := ParsePaymentRequest(uri)
+			if err != nil {
+				t.Fatalf("ParsePaymentRequest(%s): %s", uri, err)
+			}
+			if request != test.request {
+				t.Errorf("ParsePaymentRequest(%s) = %+v, want %+v", uri, request, test.request)
+			}
+		})
+	}
+}
+
+func TestParsePaymentRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		want PaymentRequest
+		// err is a part of the expected error, empty when the request is valid
+		err string
+	}{
+		{
+			name: "lower case address, + as space and unknown optional parameter",
+			uri:  " archethic:" + strings.ToLower(testPaymentAddress) + "?memo=Order+42&label=shop ",
+			want: PaymentRequest{Address: testPaymentAddress, Memo: "Order 42"},
+		},
+		{
+			name: "address after //",
+			uri:  "archethic://" + testPaymentAddress + "?amount=3",
+			want: PaymentRequest{Address: testPaymentAddress, Amount: 300_000_000},
+		},
+		{
+			name: "required variant of a known parameter",
+			uri:  "archethic:" + testPaymentAddress + "?amount=1&req-amount=1",
+			err:  "unsupported parameter req-amount",
+		},
+		{
+			name: "unknown required parameter",
+			uri:  "archethic:" + testPaymentAddress + "?req-expires=1700000000",
+			err:  "unsupported parameter req-expires",
+		},
+		{
+			name: "other scheme",
+			uri:  "bitcoin:" + testPaymentAddress,
+			err:  "must start with archethic:",
+		},
+		{
+			name: "address not hexadecimal",
+			uri:  "archethic:shop",
+			err:  "not hexadecimal",
+		},
+		{
+			name: "amount with more than 8 decimals",
+			uri:  "archethic:" + testPaymentAddress + "?amount=0.000000001",
+			err:  "more than 8 decimals",
+		},
+		{
+			name: "invalid token id",
+			uri:  "archethic:" + testPaymentAddress + "?token=" + testTokenAddress + "&token_id=one",
+			err:  "invalid token id",
+		},
+		{
+			name: "negative token id",
+			uri:  "archethic:" + testPaymentAddress + "?token=" + testTokenAddress + "&token_id=-1",
+			err:  "cannot be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := ParsePaymentRequest(test.uri)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("ParsePaymentRequest(%s) error = %v, want %s", test.uri, err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePaymentRequest(%s): %s", test.uri, err)
+			}
+			if request != test.want {
+				t.Errorf("ParsePaymentRequest(%s) = %+v, want %+v", test.uri, request, test.want)
+			}
+		})
+	}
+}
+
+func TestAmountRoundTrip(t *testing.T) {
+	tests := []struct {
+		amount    uint64
+		formatted string
+	}{
+		{0, "0"},
+		{1, "0.00000001"},
+		{100_000_000, "1"},
+		{1_250_000_000, "12.5"},
+		{123_456_789, "1.23456789"},
+		{18_446_744_073_709_551_615, "184467440737.09551615"},
+	}
+
+	for _, test := range tests {
+		formatted := FormatAmount(test.amount)
+		if formatted != test.formatted {
+			t.Errorf("FormatAmount(%d) = %s, want %s", test.amount, formatted, test.formatted)
+		}
+		amount, err := ParseAmount(formatted)
+		if err != nil || amount != test.amount {
+			t.Errorf("ParseAmount(%s) = %d, %v, want %d", formatted, amount, err, test.amount)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   uint64
+		valid  bool
+	}{
+		{"12", 1_200_000_000, true},
+		{" 12.50 ", 1_250_000_000, true},
+		{".5", 50_000_000, true},
+		{"5.", 500_000_000, true},
+		{"0.12345678", 12_345_678, true},
+		{"", 0, false},
+		{".", 0, false},
+		{"0.123456789", 0, false},
+		{"-1", 0, false},
+		{"1,5", 0, false},
+		{"1.2.3", 0, false},
+		{"184467440737.09551616", 0, false},
+	}
+
+	for _, test := range tests {
+		amount, err := ParseAmount(test.amount)
+		if test.valid != (err == nil) || amount != test.want {
+			t.Errorf("ParseAmount(%q) = %d, %v, want %d (valid: %v)", test.amount, amount, err, test.want, test.valid)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// NextIndex is the index asking to send the transaction at the index following the node's index and the pending transactions of the chain
+const NextIndex = -1
+
+// pendingTimeout is the age after which a reservation is considered abandoned (the process sending it was stopped)
+const pendingTimeout = 10 * time.Minute
+
+// PendingTransaction is the index of a chain reserved by a transaction being sent, until the transaction is confirmed
+type PendingTransaction struct {
+	Chain     string    `json:"chain"`
+	Index     int       `json:"index"`
+	Address   string    `json:"address,omitempty"`
+	Endpoint  string    `json:"endpoint"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Stale returns true when the reservation is too old to be a transaction still being sent
+func (p PendingTransaction) Stale() bool {
+	return time.Since(p.CreatedAt) > pendingTimeout
+}
+
+// pendingJournalPath returns the location of the journal of the pending transactions: <user cache dir>/archethic-cli/pending.json
+func pendingJournalPath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "archethic-cli", "pending.json")
+}
+
+// withPendingJournal runs the update on the pending transactions while holding the lock of the journal,
+// so the processes sending from the same chain don't reserve the same index, and saves the updated journal
+func withPendingJournal(update func(pending []PendingTransaction) ([]PendingTransaction, error)) error {
+	path := pendingJournalPath()
+	if path == "" {
+		return errors.New("no cache directory for the journal of the pending transactions")
+	}
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	err = lockFile(lock)
+	if err != nil {
+		return err
+	}
+	defer unlockFile(lock)
+
+	var pending []PendingTransaction
+	journalBytes, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if len(journalBytes) > 0 {
+		err = json.Unmarshal(journalBytes, &pending)
+		if err != nil {
+			return err
+		}
+	}
+	pending, err = update(pending)
+	if err != nil {
+		return err
+	}
+	journalBytes, err = json.MarshalIndent(pending, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the journal is replaced atomically, a reader never sees a partial file
+	tmpPath := path + ".tmp"
+	err = os.WriteFile(tmpPath, journalBytes, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ReserveIndex reserves an index of the chain. With NextIndex, it reserves the index of the next transaction of the chain:
+// the index known by the node, or the index following the transactions of the chain still pending. Otherwise the given
+// index is reserved as is, and an error is returned when a transaction being sent already reserved it.
+// The reservations confirmed by the node (lower than its index) and the abandoned ones are removed.
+func ReserveIndex(endpoint string, chain string, nodeIndex int, index int) (PendingTransaction, error) {
+	chain = strings.ToUpper(chain)
+	reservation := PendingTransaction{Chain: chain, Index: index, Endpoint: endpoint, CreatedAt: time.Now()}
+	if index == NextIndex {
+		reservation.Index = nodeIndex
+	}
+	err := withPendingJournal(func(pending []PendingTransaction) ([]PendingTransaction, error) {
+		kept := pending[:0]
+		for _, p := range pending {
+			if p.Chain == chain && (p.Index < nodeIndex || p.Stale()) {
+				continue
+			}
+			if p.Chain == chain && index != NextIndex && p.Index == index {
+				return nil, fmt.Errorf("the index %d of the chain is reserved by a transaction being sent (see the pending command)", index)
+			}
+			if p.Chain == chain && index == NextIndex && p.Index >= reservation.Index {
+				reservation.Index = p.Index + 1
+			}
+			kept = append(kept, p)
+		}
+		return append(kept, reservation), nil
+	})
+	if err != nil {
+		return PendingTransaction{}, err
+	}
+	return reservation, nil
+}
+
+// updatePending sets the address of the reserved transaction once it is built
+func updatePending(reservation PendingTransaction, address string) {
+	err := withPendingJournal(func(pending []PendingTransaction) ([]PendingTransaction, error) {
+		for i, p := range pending {
+			if p.Chain == reservation.Chain && p.Index == reservation.Index {
+				pending[i].Address = address
+			}
+		}
+		return pending, nil
+	})
+	if err != nil {
+		logging.Warn("cannot update the pending transaction", "chain", reservation.Chain, "index", reservation.Index, "error", err)
+	}
+}
+
+// releasePending removes the reservation, when its transaction is confirmed or failed
+func releasePending(reservation PendingTransaction) {
+	err := withPendingJournal(func(pending []PendingTransaction) ([]PendingTransaction, error) {
+		kept := pending[:0]
+		for _, p := range pending {
+			if p.Chain != reservation.Chain || p.Index != reservation.Index {
+				kept = append(kept, p)
+			}
+		}
+		return kept, nil
+	})
+	if err != nil {
+		logging.Warn("cannot release the pending transaction", "chain", reservation.Chain, "index", reservation.Index, "error", err)
+	}
+}
+
+// trackPending releases the reservation once the node confirms the transaction or rejects it.
+// A transaction neither confirmed nor rejected keeps its reservation until the node's index passes it, or it is abandoned.
+func trackPending(ts *archethic.TransactionSender, reservation PendingTransaction) {
+	ts.AddOnRequiredConfirmation(func(nbConf int) {
+		releasePending(reservation)
+	})
+	ts.AddOnError(func(senderContext string, message error) {
+		releasePending(reservation)
+	})
+}
+
+// reserveTransactionIndex reserves the index of the transaction in its chain, the seed's chain or the keychain service's chain.
+// The index of the seed's chain is given by the caller (NextIndex to use the next free one), the one of the service's chain
+// is always the next free one. The node's index of the chain is fetched to remove the confirmed reservations.
+func reserveTransactionIndex(endpoint string, seed []byte, curve archethic.Curve, serviceMode bool, serviceName string, index int) (PendingTransaction, error) {
+	if !serviceMode {
+		serviceName = ""
+	} else {
+		index = NextIndex
+	}
+	genesisAddress, err := GenesisAddress(endpoint, seed, curve, serviceName)
+	if err != nil {
+		return PendingTransaction{}, err
+	}
+	chain := hex.EncodeToString(genesisAddress)
+	nodeIndex, err := GetLastTransactionIndexOfAddress(endpoint, chain)
+	if err != nil {
+		return PendingTransaction{}, err
+	}
+	return ReserveIndex(endpoint, chain, nodeIndex, index)
+}
+
+// PendingTransactions returns the reservations of the journal
+func PendingTransactions() ([]PendingTransaction, error) {
+	var result []PendingTransaction
+	err := withPendingJournal(func(pending []PendingTransaction) ([]PendingTransaction, error) {
+		result = append(result, pending...)
+		return pending, nil
+	})
+	return result, err
+}
+
+// ReconcilePending removes the reservations confirmed by the node of their endpoint.
+// The reservations whose chain can't be fetched are kept.
+func ReconcilePending() ([]PendingTransaction, error) {
+	pending, err := PendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+	nodeIndexes := map[string]int{}
+	for _, p := range pending {
+		if _, ok := nodeIndexes[p.Chain]; ok {
+			continue
+		}
+		nodeIndex, err := GetLastTransactionIndexOfAddress(p.Endpoint, p.Chain)
+		if err != nil {
+			logging.Warn("cannot reconcile the pending transactions", "chain", p.Chain, "endpoint", p.Endpoint, "error", err)
+			nodeIndex = -1
+		}
+		nodeIndexes[p.Chain] = nodeIndex
+	}
+	var result []PendingTransaction
+	err = withPendingJournal(func(pending []PendingTransaction) ([]PendingTransaction, error) {
+		kept := pending[:0]
+		for _, p := range pending {
+			if nodeIndex, ok := nodeIndexes[p.Chain]; ok && p.Index < nodeIndex {
+				continue
+			}
+			kept = append(kept, p)
+		}
+		result = append(result, kept...)
+		return kept, nil
+	})
+	return result, err
+}
+
+// ClearPending removes the reservations of the chain, or all of them when the chain is empty, and returns the number removed
+func ClearPending(chain string) (int, error) {
+	removed := 0
+	err := withPendingJournal(func(pending []PendingTransaction) ([]PendingTransaction, error) {
+		kept := pending[:0]
+		for _, p := range pending {
+			if chain == "" || strings.EqualFold(p.Chain, chain) {
+				removed++
+				continue
+			}
+			kept = append(kept, p)
+		}
+		return kept, nil
+	})
+	return removed, err
+}
@@ -0,0 +1,134 @@
+package tuiutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const (
+	testChain      = "0000AAAA"
+	testOtherChain = "0000BBBB"
+)
+
+// usePendingJournal uses an empty journal of the pending transactions for the test
+func usePendingJournal(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv("HOME", cacheDir)
+	t.Setenv("LocalAppData", cacheDir)
+}
+
+// chainLengthNode is a node answering the length of the test chain, and an error for the other chains
+func chainLengthNode(t *testing.T, chainLength int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables struct {
+				Address string `json:"address"`
+			} `json:"variables"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			t.Errorf("invalid request: %s", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if request.Variables.Address != testChain {
+			w.Write([]byte(`{"errors": [{"message": "unavailable"}]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"lastTransaction": map[string]int{"chainLength": chainLength}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReserveIndex(t *testing.T) {
+	usePendingJournal(t)
+
+	first, err := ReserveIndex("http://node", testChain, 2, NextIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ReserveIndex("http://node", testChain, 2, NextIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Index != 2 || second.Index != 3 {
+		t.Errorf("reserved the indexes %d and %d, want 2 and 3", first.Index, second.Index)
+	}
+
+	other, err := ReserveIndex("http://node", testOtherChain, 0, NextIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Index != 0 {
+		t.Errorf("reserved the index %d of another chain, want 0", other.Index)
+	}
+
+	_, err = ReserveIndex("http://node", testChain, 2, 3)
+	if err == nil {
+		t.Error("the index 3 reserved twice")
+	}
+	explicit, err := ReserveIndex("http://node", testChain, 2, 7)
+	if err != nil || explicit.Index != 7 {
+		t.Errorf("reserved the index %d, %v, want 7", explicit.Index, err)
+	}
+
+	// the node confirmed the transactions up to the index 3: the next index follows the reservation 7
+	next, err := ReserveIndex("http://node", testChain, 4, NextIndex)
+	if err != nil || next.Index != 8 {
+		t.Errorf("reserved the index %d, %v, want 8", next.Index, err)
+	}
+	pending, err := PendingTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pending {
+		if p.Chain == testChain && p.Index < 4 {
+			t.Errorf("the confirmed index %d is still reserved", p.Index)
+		}
+	}
+}
+
+func TestReconcilePending(t *testing.T) {
+	usePendingJournal(t)
+	node := chainLengthNode(t, 2)
+
+	for _, index := range []int{0, 1, 2, 3} {
+		_, err := ReserveIndex(node.URL, testChain, 0, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := ReserveIndex(node.URL, testOtherChain, 0, NextIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the chain has 2 transactions: the indexes 0 and 1 are confirmed, the chain of the failed query is kept
+	pending, err := ReconcilePending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for _, p := range pending {
+		kept = append(kept, fmt.Sprintf("%s#%d", p.Chain, p.Index))
+	}
+	want := []string{testChain + "#2", testChain + "#3", testOtherChain + "#0"}
+	if !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+
+	saved, err := PendingTransactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != len(want) {
+		t.Errorf("%d reservations saved, want %d", len(saved), len(want))
+	}
+}
@@ -0,0 +1,135 @@
+package tuiutils
+
+import (
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// Status of a transaction sent during the session
+const (
+	StatusPending   = "pending"
+	StatusSent      = "sent"
+	StatusConfirmed = "confirmed"
+	StatusFailed    = "failed"
+)
+
+// SentTransaction describes a transaction sent during the session and its confirmation status
+type SentTransaction struct {
+	Address          string
+	Type             string
+	Endpoint         string
+	SentAt           time.Time
+	Status           string
+	Confirmations    int
+	MaxConfirmations int
+	Error            string
+}
+
+// LoadedChain describes the transaction chain (seed or keychain service) last used to build a transaction
+type LoadedChain struct {
+	Endpoint    string
+	Seed        []byte
+	Curve       archethic.Curve
+	ServiceName string
+}
+
+var session struct {
+	sync.Mutex
+	sentTransactions []SentTransaction
+	loadedChain      *LoadedChain
+}
+
+// SentTransactions returns a copy of the transactions sent during the session, the most recent first
+func SentTransactions() []SentTransaction {
+	session.Lock()
+	defer session.Unlock()
+	transactions := make([]SentTransaction, len(session.sentTransactions))
+	for i, transaction := range session.sentTransactions {
+		transactions[len(transactions)-1-i] = transaction
+	}
+	return transactions
+}
+
+// sentTransaction returns the last transaction sent during the session with this address
+func sentTransaction(address string) (SentTransaction, bool) {
+	session.Lock()
+	defer session.Unlock()
+	for i := len(session.sentTransactions) - 1; i >= 0; i-- {
+		if session.sentTransactions[i].Address == address {
+			return session.sentTransactions[i], true
+		}
+	}
+	return SentTransaction{}, false
+}
+
+// SetLoadedChain records the transaction chain currently used by the interface
+func SetLoadedChain(chain LoadedChain) {
+	session.Lock()
+	defer session.Unlock()
+	session.loadedChain = &chain
+}
+
+// GetLoadedChain returns the transaction chain currently used by the interface, if any
+func GetLoadedChain() (LoadedChain, bool) {
+	session.Lock()
+	defer session.Unlock()
+	if session.loadedChain == nil {
+		return LoadedChain{}, false
+	}
+	return *session.loadedChain, true
+}
+
+// trackTransaction registers the transaction in the session and updates its status from the sender's events
+func trackTransaction(ts *archethic.TransactionSender, transaction *archethic.TransactionBuilder, endpoint string) {
+	address := strings.ToUpper(hex.EncodeToString(transaction.Address))
+	session.Lock()
+	session.sentTransactions = append(session.sentTransactions, SentTransaction{
+		Address:  address,
+		Type:     GetTransactionTypeName(transaction.TxType),
+		Endpoint: endpoint,
+		SentAt:   time.Now(),
+		Status:   StatusPending,
+	})
+	session.Unlock()
+
+	ts.AddOnSent(func() {
+		updateSentTransaction(address, func(t *SentTransaction) {
+			t.Status = StatusSent
+		})
+	})
+	ts.AddOnConfirmation(func(nbConf, maxConf int) {
+		updateSentTransaction(address, func(t *SentTransaction) {
+			t.Confirmations = nbConf
+			t.MaxConfirmations = maxConf
+		})
+	})
+	ts.AddOnRequiredConfirmation(func(nbConf int) {
+		updateSentTransaction(address, func(t *SentTransaction) {
+			t.Status = StatusConfirmed
+			t.Confirmations = nbConf
+		})
+	})
+	ts.AddOnError(func(senderContext string, message error) {
+		updateSentTransaction(address, func(t *SentTransaction) {
+			t.Status = StatusFailed
+			if err := handleTransactionError(message); err != nil {
+				t.Error = err.Error()
+			}
+		})
+	})
+}
+
+func updateSentTransaction(address string, update func(*SentTransaction)) {
+	session.Lock()
+	defer session.Unlock()
+	for i := len(session.sentTransactions) - 1; i >= 0; i-- {
+		if session.sentTransactions[i].Address == address {
+			update(&session.sentTransactions[i])
+			return
+		}
+	}
+}
@@ -14,9 +14,12 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/archethic-foundation/archethic-cli/logging"
 	archethic "github.com/archethic-foundation/libgo"
 	"github.com/spf13/pflag"
 	"github.com/tyler-smith/go-bip39"
@@ -55,7 +58,101 @@ func GetCurveName(h archethic.Curve) string {
 	panic("Unknown curve")
 }
 
+// GetTransactionTypeName returns the name of the transaction type as used by the node's API
+func GetTransactionTypeName(t archethic.TransactionType) string {
+	switch t {
+	case archethic.KeychainAccessType:
+		return "keychain_access"
+	case archethic.KeychainType:
+		return "keychain"
+	case archethic.TransferType:
+		return "transfer"
+	case archethic.HostingType:
+		return "hosting"
+	case archethic.TokenType:
+		return "token"
+	case archethic.DataType:
+		return "data"
+	case archethic.ContractType:
+		return "contract"
+	case archethic.CodeProposalType:
+		return "code_proposal"
+	case archethic.CodeApprovalType:
+		return "code_approval"
+	}
+	return fmt.Sprintf("unknown (%d)", t)
+}
+
+// DerivedKeypair describes the keypair and address derived from a seed at a given index
+type DerivedKeypair struct {
+	Index     uint32 `json:"index"`
+	Address   string `json:"address"`
+	PublicKey string `json:"public_key"`
+	Curve     string `json:"curve"`
+}
+
+// MaxIndexRange is the maximum number of indexes derived at once
+const MaxIndexRange = 10000
+
+// CheckIndexRange returns an error when the range of indexes is reversed or holds more than MaxIndexRange indexes
+func CheckIndexRange(from uint32, to uint32) error {
+	if to < from {
+		return errors.New("the last index must be greater than or equal to the first index")
+	}
+	if uint64(to)-uint64(from)+1 > MaxIndexRange {
+		return fmt.Errorf("the range of indexes cannot hold more than %d indexes", MaxIndexRange)
+	}
+	return nil
+}
+
+// DeriveKeypairs derives the keypairs and addresses of the seed for each index between from and to (inclusive)
+func DeriveKeypairs(seed []byte, from uint32, to uint32, curve archethic.Curve, hashAlgo archethic.HashAlgo) ([]DerivedKeypair, error) {
+	if err := CheckIndexRange(from, to); err != nil {
+		return nil, err
+	}
+	keypairs := make([]DerivedKeypair, 0, to-from+1)
+	for index := from; ; index++ {
+		publicKey, _, err := archethic.DeriveKeypair(seed, index, curve)
+		if err != nil {
+			return nil, err
+		}
+		address, err := archethic.DeriveAddress(seed, index, curve, hashAlgo)
+		if err != nil {
+			return nil, err
+		}
+		keypairs = append(keypairs, DerivedKeypair{
+			Index:     index,
+			Address:   hex.EncodeToString(address),
+			PublicKey: hex.EncodeToString(publicKey),
+			Curve:     GetCurveName(curve),
+		})
+		// avoid overflowing when to is the max uint32 value
+		if index == to {
+			break
+		}
+	}
+	return keypairs, nil
+}
+
+// FindAddressIndex scans the indexes of the seed (up to maxIndex) to find the one at which the address was derived
+func FindAddressIndex(seed []byte, address []byte, maxIndex uint32, curve archethic.Curve, hashAlgo archethic.HashAlgo) (uint32, error) {
+	for index := uint32(0); ; index++ {
+		derivedAddress, err := archethic.DeriveAddress(seed, index, curve, hashAlgo)
+		if err != nil {
+			return 0, err
+		}
+		if reflect.DeepEqual(derivedAddress, address) {
+			return index, nil
+		}
+		if index == maxIndex {
+			break
+		}
+	}
+	return 0, fmt.Errorf("address not derived from this seed between index 0 and %d", maxIndex)
+}
+
 func CreateKeychain(url string, accessSeed []byte) (string, string, string, string, error) {
+	logging.AddSecretBytes(accessSeed)
 	originPrivateKey, _ := hex.DecodeString("01019280BDB84B8F8AEDBA205FE3552689964A5626EE2C60AA10E3BF22A91A036009")
 
 	publicKey, _, err := archethic.DeriveKeypair(accessSeed, 0, archethic.ED25519)
@@ -65,6 +162,7 @@ func CreateKeychain(url string, accessSeed []byte) (string, string, string, stri
 
 	randomSeed := make([]byte, 32)
 	rand.Read(randomSeed)
+	logging.AddSecretBytes(randomSeed)
 
 	keychain := archethic.NewKeychain(randomSeed)
 	keychain.AddService("uco", "m/650'/0/0", archethic.ED25519, archethic.SHA256)
@@ -85,19 +183,28 @@ func CreateKeychain(url string, accessSeed []byte) (string, string, string, stri
 	}
 	keychainTx.OriginSign(originPrivateKey)
 
-	client := archethic.NewAPIClient(url)
-	accessKeychain, _ := archethic.GetKeychain(accessSeed, *client)
+	accessKeychain, _ := getKeychain(url, accessSeed)
 	if accessKeychain != nil {
 		err = errors.New("keychain access already exists")
 		return "", "", "", "", err
 	}
 
+	// the transactions are sent only once, to the first healthy node
+	url, err = SelectEndpoint(url)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	client := archethic.NewAPIClient(url)
+
 	var returnedError error
 	feedback := ""
 	keychainSeed := ""
 	keychainTransactionAddress := ""
 	keychainAccessTransactionAddress := ""
+	keychainFee := journalFee(client, keychainTx, nil)
 	ts := archethic.NewTransactionSender(client)
+	trackTransaction(ts, keychainTx, url)
+	journalKeychain := trackJournal(ts, keychainTx, url, keychainFee)
 	ts.AddOnRequiredConfirmation(func(nbConf int) {
 		feedback += "\nKeychain's transaction confirmed."
 
@@ -110,7 +217,10 @@ func CreateKeychain(url string, accessSeed []byte) (string, string, string, stri
 			feedback = err.Error()
 		}
 		accessTx.OriginSign(originPrivateKey)
+		accessFee := journalFee(client, accessTx, nil)
 		ts2 := archethic.NewTransactionSender(client)
+		trackTransaction(ts2, accessTx, url)
+		journalAccess := trackJournal(ts2, accessTx, url, accessFee)
 		ts2.AddOnRequiredConfirmation(func(nbConf int) {
 			feedback += "\nKeychain access transaction confirmed."
 			ts2.Unsubscribe("confirmation")
@@ -120,7 +230,10 @@ func CreateKeychain(url string, accessSeed []byte) (string, string, string, stri
 			feedback += fmt.Sprintf("\nAccess transaction error: %s", handleTransactionError(message))
 			ts.Unsubscribe("error")
 		})
+		start := time.Now()
 		ts2.SendTransaction(accessTx, 100, 60)
+		logging.Info("keychain access transaction sent", "endpoint", url, "address", accessTx.Address, "duration", time.Since(start))
+		journalAccess()
 		ts.Unsubscribe("confirmation")
 	})
 	ts.AddOnError(func(senderContext string, message error) {
@@ -128,13 +241,15 @@ func CreateKeychain(url string, accessSeed []byte) (string, string, string, stri
 		feedback += fmt.Sprintf("Keychain transaction error: %s", returnedError)
 		ts.Unsubscribe("error")
 	})
+	start := time.Now()
 	ts.SendTransaction(keychainTx, 100, 60)
+	logging.Info("keychain transaction sent", "endpoint", url, "address", keychainTx.Address, "duration", time.Since(start))
+	journalKeychain()
 	return feedback, keychainSeed, keychainTransactionAddress, keychainAccessTransactionAddress, returnedError
 }
 
 func AccessKeychain(endpoint string, seed []byte) (*archethic.Keychain, error) {
-	client := archethic.NewAPIClient(endpoint)
-	return archethic.GetKeychain(seed, *client)
+	return getKeychain(endpoint, seed)
 }
 
 func AddServiceToKeychain(accessSeed []byte, endpoint string, serviceName string, serviceDerivationPath string) (string, error) {
@@ -150,8 +265,7 @@ func RemoveServiceFromKeychain(accessSeed []byte, endpoint string, serviceName s
 }
 
 func updateKeychain(accessSeed []byte, endpoint string, updateFunc func(*archethic.Keychain)) (string, error) {
-	client := *archethic.NewAPIClient(endpoint)
-	keychain, err := archethic.GetKeychain(accessSeed, client)
+	keychain, err := getKeychain(endpoint, accessSeed)
 	if err != nil {
 		return "", err
 	}
@@ -162,11 +276,19 @@ func updateKeychain(accessSeed []byte, endpoint string, updateFunc func(*archeth
 		return "", err
 	}
 	addressHex := hex.EncodeToString(keychainGenesisAddress)
-	transactionChainIndex := client.GetLastTransactionIndex(addressHex)
-	transaction, err := archethic.NewKeychainTransaction(keychain, uint32(transactionChainIndex))
+	transactionChainIndex, err := GetLastTransactionIndexOfAddress(endpoint, addressHex)
+	if err != nil {
+		return "", err
+	}
+	reservation, err := ReserveIndex(endpoint, addressHex, transactionChainIndex, NextIndex)
 	if err != nil {
 		return "", err
 	}
+	transaction, err := archethic.NewKeychainTransaction(keychain, uint32(reservation.Index))
+	if err != nil {
+		releasePending(reservation)
+		return "", err
+	}
 	originPrivateKey, _ := hex.DecodeString("01019280BDB84B8F8AEDBA205FE3552689964A5626EE2C60AA10E3BF22A91A036009")
 	transaction.OriginSign(originPrivateKey)
 
@@ -174,7 +296,17 @@ func updateKeychain(accessSeed []byte, endpoint string, updateFunc func(*archeth
 	var returnedFeedback = ""
 	returnedError = nil
 
-	ts := archethic.NewTransactionSender(&client)
+	endpoint, err = SelectEndpoint(endpoint)
+	if err != nil {
+		releasePending(reservation)
+		return "", err
+	}
+	client := archethic.NewAPIClient(endpoint)
+	fee := journalFee(client, transaction, nil)
+	ts := archethic.NewTransactionSender(client)
+	trackTransaction(ts, transaction, endpoint)
+	trackPending(ts, reservation)
+	journal := trackJournal(ts, transaction, endpoint, fee)
 	ts.AddOnRequiredConfirmation(func(nbConf int) {
 		returnedFeedback = "\nKeychain's transaction confirmed."
 	})
@@ -182,19 +314,45 @@ func updateKeychain(accessSeed []byte, endpoint string, updateFunc func(*archeth
 		returnedError = handleTransactionError(message)
 		ts.Unsubscribe("error")
 	})
+	start := time.Now()
 	ts.SendTransaction(transaction, 100, 60)
+	logging.Info("keychain transaction sent", "endpoint", endpoint, "address", transaction.Address, "index", reservation.Index, "duration", time.Since(start), "error", returnedError)
+	journal()
+	if returnedError == nil {
+		cacheKeychainServices(endpoint, keychain)
+	}
 
 	return returnedFeedback, returnedError
 }
 
-func SendTransaction(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex int, serviceName string, storageNouncePublicKey string, seed []byte) (string, error) {
-	err := buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, seed)
+// SendTransaction builds the transaction with the index reserved in the journal of the pending transactions, and sends it.
+// With NextIndex, the transactions sent simultaneously from the same chain get different indexes. An explicit index is
+// used as is, the transaction is not sent when it is already reserved. The fee already estimated, to check a maximum fee,
+// is recorded in the journal, otherwise it is estimated when nil.
+func SendTransaction(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex int, serviceName string, storageNouncePublicKey string, seed []byte, estimatedFee *archethic.Fee) (string, error) {
+	reservation, err := reserveTransactionIndex(endpoint, seed, curve, serviceMode, serviceName, transactionIndex)
+	if err != nil {
+		return "", err
+	}
+	err = buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, reservation.Index, serviceName, storageNouncePublicKey, seed)
+	if err != nil {
+		releasePending(reservation)
+		return "", err
+	}
+	// the transaction is sent only once, to the first healthy node
+	endpoint, err = SelectEndpoint(endpoint)
 	if err != nil {
+		releasePending(reservation)
 		return "", err
 	}
+	updatePending(reservation, strings.ToUpper(hex.EncodeToString(transaction.Address)))
 	feedback := ""
 	client := archethic.NewAPIClient(endpoint)
+	fee := journalFee(client, transaction, estimatedFee)
 	ts := archethic.NewTransactionSender(client)
+	trackTransaction(ts, transaction, endpoint)
+	trackPending(ts, reservation)
+	journal := trackJournal(ts, transaction, endpoint, fee)
 	ts.AddOnSent(func() {
 		feedback = endpoint + "/explorer/transaction/" + strings.ToUpper(hex.EncodeToString(transaction.Address))
 	})
@@ -203,7 +361,10 @@ func SendTransaction(transaction *archethic.TransactionBuilder, secretKey []byte
 		feedback = "Transaction error: " + handleTransactionError(message).Error()
 	})
 
+	start := time.Now()
 	ts.SendTransaction(transaction, 100, 60)
+	logging.Info("transaction sent", "endpoint", endpoint, "address", transaction.Address, "index", reservation.Index, "type", GetTransactionTypeName(transaction.TxType), "duration", time.Since(start))
+	journal()
 	return feedback, nil
 }
 
@@ -220,19 +381,63 @@ func GetTransactionFeeJson(transaction *archethic.TransactionBuilder, secretKey
 }
 
 func GetTransactionFee(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex int, serviceName string, storageNouncePublicKey string, seed []byte) (archethic.Fee, error) {
+	if serviceMode {
+		// the index of the service's chain is fetched from the node
+		transactionIndex = -1
+	}
 	err := buildTransactionToSend(transaction, secretKey, curve, serviceMode, endpoint, transactionIndex, serviceName, storageNouncePublicKey, seed)
 	if err != nil {
 		return archethic.Fee{}, err
 	}
-	client := archethic.NewAPIClient(endpoint)
-	fee, err := client.GetTransactionFee(transaction)
+	var fee archethic.Fee
+	err = WithEndpoint(endpoint, func(endpoint string) error {
+		var err error
+		fee, err = archethic.NewAPIClient(endpoint).GetTransactionFee(transaction)
+		return err
+	})
 	if err != nil {
 		return archethic.Fee{}, handleTransactionError(err)
 	}
 	return fee, nil
 }
 
+// FormatAmount converts an amount of the smallest unit (10^-8) to a decimal amount, without losing precision
+func FormatAmount(amount uint64) string {
+	decimals := strings.TrimRight(fmt.Sprintf("%08d", amount%100_000_000), "0")
+	if decimals == "" {
+		return strconv.FormatUint(amount/100_000_000, 10)
+	}
+	return fmt.Sprintf("%d.%s", amount/100_000_000, decimals)
+}
+
+// ParseAmount converts a decimal amount, with up to 8 decimals, to the smallest unit (10^-8) without losing precision
+func ParseAmount(amount string) (uint64, error) {
+	units, decimals, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if units == "" && decimals == "" {
+		return 0, errors.New("no amount")
+	}
+	if len(decimals) > 8 {
+		return 0, fmt.Errorf("the amount %s has more than 8 decimals", amount)
+	}
+	if units == "" {
+		units = "0"
+	}
+	value, err := strconv.ParseUint(units+decimals+strings.Repeat("0", 8-len(decimals)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %s", amount)
+	}
+	return value, nil
+}
+
+// FormatFee renders the fee in UCO with its equivalent in the fiat currencies, using the rates returned by the node
+func FormatFee(fee archethic.Fee) string {
+	uco := float64(fee.Fee) / 100_000_000
+	return fmt.Sprintf("%s UCO (~ $%.4f) (~ %.4f€)", FormatAmount(uint64(fee.Fee)), uco*float64(fee.Rates.Usd), uco*float64(fee.Rates.Eur))
+}
+
 func buildTransactionToSend(transaction *archethic.TransactionBuilder, secretKey []byte, curve archethic.Curve, serviceMode bool, endpoint string, transactionIndex int, serviceName string, storageNouncePublicKey string, seed []byte) error {
+	logging.AddSecretBytes(seed)
+	logging.AddSecretBytes(secretKey)
 	if len(transaction.Data.Code) > 0 {
 		ownershipIndex := -1
 		for i, ownership := range transaction.Data.Ownerships {
@@ -265,10 +470,8 @@ func buildTransactionToSend(transaction *archethic.TransactionBuilder, secretKey
 		}
 	}
 
-	client := archethic.NewAPIClient(endpoint)
-
 	if serviceMode {
-		err := buildKeychainTransaction(seed, client, transaction, serviceName)
+		err := buildKeychainTransaction(seed, endpoint, transaction, serviceName, transactionIndex)
 		if err != nil {
 			return err
 		}
@@ -281,11 +484,13 @@ func buildTransactionToSend(transaction *archethic.TransactionBuilder, secretKey
 
 	originPrivateKey, _ := hex.DecodeString("01019280BDB84B8F8AEDBA205FE3552689964A5626EE2C60AA10E3BF22A91A036009")
 	transaction.OriginSign(originPrivateKey)
+	logging.Info("transaction built", "address", transaction.Address, "index", transactionIndex, "type", GetTransactionTypeName(transaction.TxType), "service", serviceName)
 	return nil
 }
 
-func buildKeychainTransaction(seed []byte, client *archethic.APIClient, transaction *archethic.TransactionBuilder, serviceName string) error {
-	keychain, err := archethic.GetKeychain(seed, *client)
+// buildKeychainTransaction builds the transaction of the service with the given index, or the next index of its chain when negative
+func buildKeychainTransaction(seed []byte, endpoint string, transaction *archethic.TransactionBuilder, serviceName string, index int) error {
+	keychain, err := getKeychain(endpoint, seed)
 	if err != nil {
 		return err
 	}
@@ -297,7 +502,12 @@ func buildKeychainTransaction(seed []byte, client *archethic.APIClient, transact
 		return err
 	}
 
-	index := client.GetLastTransactionIndex(hex.EncodeToString(genesisAddress))
+	if index < 0 {
+		index, err = GetLastTransactionIndexOfAddress(endpoint, hex.EncodeToString(genesisAddress))
+		if err != nil {
+			return err
+		}
+	}
 
 	err = keychain.BuildTransaction(transaction, serviceName, uint8(index))
 	if err != nil {
@@ -346,19 +556,33 @@ func promptSecret(message string) string {
 	if err != nil {
 		log.Fatalf("Failed to read secret: %v", err)
 	}
+	logging.AddSecret(string(passphrase))
 	fmt.Println()
 	return string(passphrase)
 }
 
 func GetLastTransactionIndex(url string, curve archethic.Curve, seed []byte) (int, error) {
-	client := archethic.NewAPIClient(url)
 	address, err := archethic.DeriveAddress(seed, 0, curve, archethic.SHA256)
 	if err != nil {
 		return 0, err
 	}
 	addressHex := hex.EncodeToString(address)
-	index := client.GetLastTransactionIndex(addressHex)
-	return index, nil
+	return GetLastTransactionIndexOfAddress(url, addressHex)
+}
+
+// GenesisAddress returns the genesis address of the seed's chain, or of the keychain service's chain when a service name is provided
+func GenesisAddress(endpoint string, seed []byte, curve archethic.Curve, serviceName string) ([]byte, error) {
+	if serviceName == "" {
+		return archethic.DeriveAddress(seed, 0, curve, archethic.SHA256)
+	}
+	keychain, err := AccessKeychain(endpoint, seed)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := keychain.Services[serviceName]; !ok {
+		return nil, errors.New("service " + serviceName + " not found in the keychain")
+	}
+	return keychain.DeriveAddress(serviceName, 0)
 }
 
 func GetSeedBytes(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedFlagKey, mnemonicFlag string) ([]byte, error) {
@@ -408,6 +632,7 @@ func GetSeedBytes(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedFlagKey,
 
 	// otherwise try to get the seed from the seedFlagKey
 	accessSeed, _ := flags.GetString(seedFlagKey)
+	logging.AddSecret(accessSeed)
 	// check if the provided seed looks like a mnemonic word list
 	potentialWordsList := strings.Fields(accessSeed)
 	if len(potentialWordsList) == 24 {
@@ -417,10 +642,23 @@ func GetSeedBytes(flags *pflag.FlagSet, sshFlagKey, sshPathFlagKey, seedFlagKey,
 		}
 	}
 
-	return archethic.MaybeConvertToHex(accessSeed)
+	return DecodeSecret(accessSeed)
+}
+
+// DecodeSecret converts a seed or a secret typed by the user, hexadecimal or text, to bytes.
+// Both its typed and hexadecimal forms are registered to be redacted from the logs.
+func DecodeSecret(value string) ([]byte, error) {
+	logging.AddSecret(value)
+	secret, err := archethic.MaybeConvertToHex(value)
+	if err != nil {
+		return nil, err
+	}
+	logging.AddSecretBytes(secret)
+	return secret, nil
 }
 
 func ExtractSeedFromMnemonic(words string) ([]byte, error) {
+	logging.AddSecret(words)
 	// check if it's a bip39 word list in English
 	if bip39.IsMnemonicValid(words) {
 		seed, err := bip39.EntropyFromMnemonic(words)
@@ -0,0 +1,316 @@
+package tuiutils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+
+	// limits of the transactions accepted by the nodes
+	maxContentSize     = 3 * 1024 * 1024
+	maxCodeSize        = 24 * 1024
+	maxTransfers       = 256
+	maxRecipients      = 256
+	maxOwnerships      = 256
+	maxAuthorizedKeys  = 256
+	maxTokenID         = 255
+	tokenSupplyUnit    = 100_000_000
+	maxKeyOriginID     = 3
+	uncompressedKeyLen = 65
+)
+
+// digestSizes gives the size of the digest of each hash algorithm, which follows the 2 prefix bytes of an address
+var digestSizes = map[archethic.HashAlgo]int{
+	archethic.SHA256:   32,
+	archethic.SHA512:   64,
+	archethic.SHA3_256: 32,
+	archethic.SHA3_512: 64,
+	archethic.BLAKE2B:  64,
+}
+
+// keySizes gives the size of the public key of each curve, which follows the 2 prefix bytes of a public key
+var keySizes = map[archethic.Curve]int{
+	archethic.ED25519:   32,
+	archethic.P256:      uncompressedKeyLen,
+	archethic.SECP256K1: uncompressedKeyLen,
+}
+
+// ValidationFinding is a rule of the chain broken by a transaction.
+// The field is named as in the transaction templates, with the index of the item (e.g. uco_transfers[0].amount).
+type ValidationFinding struct {
+	Severity string `json:"severity"`
+	Field    string `json:"field"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f ValidationFinding) String() string {
+	if f.Line != 0 {
+		return fmt.Sprintf("%s: %s: line %d: %s", f.Severity, f.Field, f.Line, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Field, f.Message)
+}
+
+// Section returns the part of the transaction holding the field (uco_transfers, ownerships, content...)
+func (f ValidationFinding) Section() string {
+	if i := strings.IndexAny(f.Field, "[."); i >= 0 {
+		return f.Field[:i]
+	}
+	return f.Field
+}
+
+// HasValidationErrors returns true when one of the findings prevents the transaction from being accepted
+func HasValidationErrors(findings []ValidationFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type validator struct {
+	findings []ValidationFinding
+}
+
+func (v *validator) errorf(field string, format string, args ...interface{}) {
+	v.findings = append(v.findings, ValidationFinding{Severity: SeverityError, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(field string, format string, args ...interface{}) {
+	v.findings = append(v.findings, ValidationFinding{Severity: SeverityWarning, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateTransaction checks the transaction against the rules of the chain before it is signed:
+// the format of the addresses and public keys, the amounts, the size limits and the requirements of its type
+func ValidateTransaction(transaction *archethic.TransactionBuilder) []ValidationFinding {
+	v := &validator{}
+	data := transaction.Data
+
+	if len(data.Ledger.Uco.Transfers) > maxTransfers {
+		v.errorf("uco_transfers", "at most %d UCO transfers are allowed", maxTransfers)
+	}
+	for i, transfer := range data.Ledger.Uco.Transfers {
+		field := fmt.Sprintf("uco_transfers[%d]", i)
+		v.checkAddress(field+".to", transfer.To)
+		if transfer.Amount == 0 {
+			v.errorf(field+".amount", "the amount must be positive")
+		}
+	}
+
+	if len(data.Ledger.Token.Transfers) > maxTransfers {
+		v.errorf("token_transfers", "at most %d token transfers are allowed", maxTransfers)
+	}
+	for i, transfer := range data.Ledger.Token.Transfers {
+		field := fmt.Sprintf("token_transfers[%d]", i)
+		v.checkAddress(field+".to", transfer.To)
+		v.checkAddress(field+".token_address", transfer.TokenAddress)
+		if transfer.Amount == 0 {
+			v.errorf(field+".amount", "the amount must be positive")
+		}
+		if transfer.TokenId < 0 || transfer.TokenId > maxTokenID {
+			v.errorf(field+".token_id", "the token ID must be between 0 and %d", maxTokenID)
+		}
+	}
+
+	if len(data.Recipients) > maxRecipients {
+		v.errorf("recipients", "at most %d recipients are allowed", maxRecipients)
+	}
+	for i, recipient := range data.Recipients {
+		field := fmt.Sprintf("recipients[%d]", i)
+		v.checkAddress(field, recipient)
+		for _, previous := range data.Recipients[:i] {
+			if bytes.Equal(previous, recipient) {
+				v.warnf(field, "the recipient %s is already called", strings.ToUpper(hex.EncodeToString(recipient)))
+				break
+			}
+		}
+	}
+
+	if len(data.Ownerships) > maxOwnerships {
+		v.errorf("ownerships", "at most %d ownerships are allowed", maxOwnerships)
+	}
+	for i, ownership := range data.Ownerships {
+		field := fmt.Sprintf("ownerships[%d]", i)
+		if len(ownership.AuthorizedKeys) == 0 {
+			v.errorf(field+".authorized_keys", "the secret must be shared with at least one public key")
+		} else if len(ownership.AuthorizedKeys) > maxAuthorizedKeys {
+			v.errorf(field+".authorized_keys", "at most %d authorized keys are allowed", maxAuthorizedKeys)
+		}
+		for j, authorizedKey := range ownership.AuthorizedKeys {
+			keyField := fmt.Sprintf("%s.authorized_keys[%d]", field, j)
+			v.checkPublicKey(keyField, authorizedKey.PublicKey)
+			for _, previous := range ownership.AuthorizedKeys[:j] {
+				if bytes.Equal(previous.PublicKey, authorizedKey.PublicKey) {
+					v.warnf(keyField, "the key is already authorized")
+					break
+				}
+			}
+		}
+	}
+
+	if len(data.Content) > maxContentSize {
+		v.errorf("content", "the content is %d bytes long, the limit is %d bytes", len(data.Content), maxContentSize)
+	}
+
+	if len(data.Code) > maxCodeSize {
+		v.errorf("smart_contract", "the code is %d bytes long, the limit is %d bytes", len(data.Code), maxCodeSize)
+	}
+	if len(data.Code) > 0 {
+		// the balance of the code is a heuristic, which doesn't parse the language: its issues are not blocking
+		for _, issue := range CheckContractBalance(string(data.Code)) {
+			v.findings = append(v.findings, ValidationFinding{Severity: SeverityWarning, Field: "smart_contract", Line: issue.Line, Message: issue.Message})
+		}
+		if len(data.Ownerships) == 0 {
+			v.errorf("ownerships", "a smart contract requires an ownership of the transaction's seed authorizing the storage nonce public key")
+		}
+	}
+
+	v.checkType(transaction)
+	return v.findings
+}
+
+// checkAddress checks the curve and hash algorithm prefix bytes of the address and the size of its digest
+func (v *validator) checkAddress(field string, address []byte) {
+	if len(address) < 2 {
+		v.errorf(field, "the address is too short")
+		return
+	}
+	if _, ok := keySizes[archethic.Curve(address[0])]; !ok {
+		v.errorf(field, "unknown curve %d in the first byte of the address", address[0])
+	}
+	size, ok := digestSizes[archethic.HashAlgo(address[1])]
+	if !ok {
+		v.errorf(field, "unknown hash algorithm %d in the second byte of the address", address[1])
+		return
+	}
+	if len(address) != size+2 {
+		v.errorf(field, "the address must be %d bytes long with the %s hash algorithm, got %d", size+2, GetHashAlgorithmName(archethic.HashAlgo(address[1])), len(address))
+	}
+}
+
+// checkPublicKey checks the curve and origin prefix bytes of the public key and the size of the key
+func (v *validator) checkPublicKey(field string, publicKey []byte) {
+	if len(publicKey) < 2 {
+		v.errorf(field, "the public key is too short")
+		return
+	}
+	size, ok := keySizes[archethic.Curve(publicKey[0])]
+	if !ok {
+		v.errorf(field, "unknown curve %d in the first byte of the public key", publicKey[0])
+		return
+	}
+	if publicKey[1] > maxKeyOriginID {
+		v.errorf(field, "unknown origin %d in the second byte of the public key", publicKey[1])
+	}
+	if len(publicKey) != size+2 {
+		v.errorf(field, "the public key must be %d bytes long with the %s curve, got %d", size+2, GetCurveName(archethic.Curve(publicKey[0])), len(publicKey))
+	}
+}
+
+// checkType checks the requirements of the transaction's type
+func (v *validator) checkType(transaction *archethic.TransactionBuilder) {
+	data := transaction.Data
+	hasTransfers := len(data.Ledger.Uco.Transfers) > 0 || len(data.Ledger.Token.Transfers) > 0
+	name := GetTransactionTypeName(transaction.TxType)
+
+	switch transaction.TxType {
+	case archethic.KeychainAccessType:
+		if hasTransfers {
+			v.errorf("transaction_type", "a %s transaction cannot transfer UCO or tokens", name)
+		}
+		if len(data.Ownerships) != 1 {
+			v.errorf("ownerships", "a %s transaction must have exactly one ownership, sharing the keychain's seed", name)
+		}
+		if len(data.Code) > 0 {
+			v.errorf("transaction_type", "a %s transaction cannot hold a smart contract", name)
+		}
+	case archethic.KeychainType:
+		if hasTransfers {
+			v.errorf("transaction_type", "a %s transaction cannot transfer UCO or tokens", name)
+		}
+		if len(data.Ownerships) == 0 {
+			v.errorf("ownerships", "a %s transaction must share the keychain's secret in an ownership", name)
+		}
+		if !json.Valid(data.Content) {
+			v.errorf("content", "the content of a %s transaction must be the JSON DID document of the keychain", name)
+		}
+	case archethic.TransferType:
+		if !hasTransfers && len(data.Recipients) == 0 {
+			v.warnf("transaction_type", "the %s transaction neither transfers UCO or tokens nor calls a recipient", name)
+		}
+	case archethic.TokenType:
+		v.checkTokenContent(data.Content)
+	case archethic.HostingType:
+		if !json.Valid(data.Content) {
+			v.warnf("content", "the content of a %s transaction is expected to be the JSON manifest of the website", name)
+		}
+	case archethic.DataType:
+		if len(data.Content) == 0 && len(data.Ownerships) == 0 {
+			v.warnf("content", "the %s transaction has neither content nor ownerships", name)
+		}
+	case archethic.ContractType:
+		if len(data.Code) == 0 {
+			v.errorf("smart_contract", "a %s transaction requires a smart contract", name)
+		}
+	case archethic.CodeProposalType:
+		proposal, err := ParseProposal("", string(data.Content))
+		if err != nil {
+			v.errorf("content", "the content must be a code proposal (Description: ...\\nChanges:\\n<diff>)")
+		} else if proposal.Version == "" {
+			v.errorf("content", "the changes of the code proposal must set the version of the node")
+		}
+	case archethic.CodeApprovalType:
+		if hasTransfers {
+			v.errorf("transaction_type", "a %s transaction cannot transfer UCO or tokens", name)
+		}
+		if len(data.Recipients) != 1 {
+			v.errorf("recipients", "a %s transaction must have exactly one recipient, the approved code proposal", name)
+		}
+	default:
+		v.errorf("transaction_type", "unknown transaction type %d", transaction.TxType)
+	}
+}
+
+// checkTokenContent checks the JSON definition of the token created by a token transaction
+func (v *validator) checkTokenContent(content []byte) {
+	var token struct {
+		Supply     *json.Number      `json:"supply"`
+		Type       string            `json:"type"`
+		Collection []json.RawMessage `json:"collection"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&token); err != nil {
+		v.errorf("content", "the content of a token transaction must be the JSON definition of the token: %s", err)
+		return
+	}
+	if token.Supply == nil {
+		v.errorf("content", "the supply of the token is required")
+	} else if supply, err := token.Supply.Int64(); err != nil || supply <= 0 {
+		v.errorf("content", "the supply of the token must be a positive integer (in 10^-8 units)")
+	} else if token.Type == "non-fungible" {
+		if supply%tokenSupplyUnit != 0 {
+			v.errorf("content", "the supply of a non-fungible token must be a multiple of %d", tokenSupplyUnit)
+		} else if token.Collection != nil && int64(len(token.Collection)) != supply/tokenSupplyUnit {
+			v.errorf("content", "the collection must describe the %d tokens of the supply", supply/tokenSupplyUnit)
+		}
+	}
+	switch token.Type {
+	case "fungible":
+		if token.Collection != nil {
+			v.errorf("content", "a fungible token cannot have a collection")
+		}
+	case "non-fungible":
+	default:
+		v.errorf("content", "the type of the token must be fungible or non-fungible")
+	}
+}
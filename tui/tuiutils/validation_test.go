@@ -0,0 +1,271 @@
+package tuiutils
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// testAddress returns an ED25519/SHA256 address whose digest is filled with the byte
+func testAddress(b byte) []byte {
+	return append([]byte{byte(archethic.ED25519), byte(archethic.SHA256)}, bytes.Repeat([]byte{b}, 32)...)
+}
+
+// testPublicKey returns an ED25519 public key generated by software, filled with the byte
+func testPublicKey(b byte) []byte {
+	return append([]byte{byte(archethic.ED25519), 0}, bytes.Repeat([]byte{b}, 32)...)
+}
+
+func testOwnership(keys ...[]byte) archethic.Ownership {
+	ownership := archethic.Ownership{Secret: []byte("secret")}
+	for _, key := range keys {
+		ownership.AuthorizedKeys = append(ownership.AuthorizedKeys, archethic.AuthorizedKey{PublicKey: key})
+	}
+	return ownership
+}
+
+func TestValidateTransaction(t *testing.T) {
+	const proposal = "Description: fix\nChanges:\ndiff --git a/mix.exs b/mix.exs\n+      version: \"1.2.3\",\n"
+
+	tests := []struct {
+		name string
+		// txType is the type of the transaction, data its content
+		txType archethic.TransactionType
+		data   archethic.TransactionData
+		// want lists the severity and the field of the findings, in the order they are reported
+		want []string
+	}{
+		{
+			name:   "valid UCO transfer",
+			txType: archethic.TransferType,
+			data:   archethic.TransactionData{Ledger: archethic.Ledger{Uco: archethic.UcoLedger{Transfers: []archethic.UcoTransfer{{To: testAddress(1), Amount: 1}}}}},
+		},
+		{
+			name:   "address too short",
+			txType: archethic.TransferType,
+			data:   archethic.TransactionData{Ledger: archethic.Ledger{Uco: archethic.UcoLedger{Transfers: []archethic.UcoTransfer{{To: []byte{0}, Amount: 1}}}}},
+			want:   []string{"error uco_transfers[0].to"},
+		},
+		{
+			name:   "address of an unknown hash algorithm",
+			txType: archethic.TransferType,
+			data:   archethic.TransactionData{Ledger: archethic.Ledger{Uco: archethic.UcoLedger{Transfers: []archethic.UcoTransfer{{To: append([]byte{0, 9}, make([]byte, 32)...), Amount: 1}}}}},
+			want:   []string{"error uco_transfers[0].to"},
+		},
+		{
+			name:   "address of the wrong size",
+			txType: archethic.TransferType,
+			data:   archethic.TransactionData{Ledger: archethic.Ledger{Uco: archethic.UcoLedger{Transfers: []archethic.UcoTransfer{{To: testAddress(1)[:20], Amount: 1}}}}},
+			want:   []string{"error uco_transfers[0].to"},
+		},
+		{
+			name:   "UCO transfer without amount",
+			txType: archethic.TransferType,
+			data:   archethic.TransactionData{Ledger: archethic.Ledger{Uco: archethic.UcoLedger{Transfers: []archethic.UcoTransfer{{To: testAddress(1)}}}}},
+			want:   []string{"error uco_transfers[0].amount"},
+		},
+		{
+			name:   "token transfer with an invalid token ID",
+			txType: archethic.TransferType,
+			data:   archethic.TransactionData{Ledger: archethic.Ledger{Token: archethic.TokenLedger{Transfers: []archethic.TokenTransfer{{To: testAddress(1), TokenAddress: testAddress(2), TokenId: 256, Amount: 1}}}}},
+			want:   []string{"error token_transfers[0].token_id"},
+		},
+		{
+			name:   "too many UCO transfers",
+			txType: archethic.TransferType,
+			data: archethic.TransactionData{Ledger: archethic.Ledger{Uco: archethic.UcoLedger{Transfers: func() []archethic.UcoTransfer {
+				transfers := make([]archethic.UcoTransfer, maxTransfers+1)
+				for i := range transfers {
+					transfers[i] = archethic.UcoTransfer{To: testAddress(1), Amount: 1}
+				}
+				return transfers
+			}()}}},
+			want: []string{"error uco_transfers"},
+		},
+		{
+			name:   "recipient called twice",
+			txType: archethic.TransferType,
+			data:   archethic.TransactionData{Recipients: [][]byte{testAddress(1), testAddress(1)}},
+			want:   []string{"warning recipients[1]"},
+		},
+		{
+			name:   "ownership without authorized key",
+			txType: archethic.DataType,
+			data:   archethic.TransactionData{Ownerships: []archethic.Ownership{testOwnership()}},
+			want:   []string{"error ownerships[0].authorized_keys"},
+		},
+		{
+			name:   "key authorized twice",
+			txType: archethic.DataType,
+			data:   archethic.TransactionData{Ownerships: []archethic.Ownership{testOwnership(testPublicKey(1), testPublicKey(1))}},
+			want:   []string{"warning ownerships[0].authorized_keys[1]"},
+		},
+		{
+			name:   "public key of an unknown origin",
+			txType: archethic.DataType,
+			data:   archethic.TransactionData{Ownerships: []archethic.Ownership{testOwnership(append([]byte{0, 7}, make([]byte, 32)...))}},
+			want:   []string{"error ownerships[0].authorized_keys[0]"},
+		},
+		{
+			name:   "content too large",
+			txType: archethic.DataType,
+			data:   archethic.TransactionData{Content: make([]byte, maxContentSize+1)},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "smart contract too large",
+			txType: archethic.ContractType,
+			data:   archethic.TransactionData{Code: []byte(strings.Repeat(" ", maxCodeSize+1)), Ownerships: []archethic.Ownership{testOwnership(testPublicKey(1))}},
+			want:   []string{"error smart_contract"},
+		},
+		{
+			name:   "smart contract without ownership",
+			txType: archethic.ContractType,
+			data:   archethic.TransactionData{Code: []byte("actions triggered_by: transaction do\nend")},
+			want:   []string{"error ownerships"},
+		},
+		{
+			name:   "unbalanced smart contract",
+			txType: archethic.ContractType,
+			data:   archethic.TransactionData{Code: []byte("actions triggered_by: transaction do\n"), Ownerships: []archethic.Ownership{testOwnership(testPublicKey(1))}},
+			want:   []string{"warning smart_contract"},
+		},
+		{
+			name:   "keychain access with transfers",
+			txType: archethic.KeychainAccessType,
+			data: archethic.TransactionData{
+				Ledger:     archethic.Ledger{Uco: archethic.UcoLedger{Transfers: []archethic.UcoTransfer{{To: testAddress(1), Amount: 1}}}},
+				Ownerships: []archethic.Ownership{testOwnership(testPublicKey(1))},
+			},
+			want: []string{"error transaction_type"},
+		},
+		{
+			name:   "keychain access with token transfers and without ownership",
+			txType: archethic.KeychainAccessType,
+			data:   archethic.TransactionData{Ledger: archethic.Ledger{Token: archethic.TokenLedger{Transfers: []archethic.TokenTransfer{{To: testAddress(1), TokenAddress: testAddress(2), Amount: 1}}}}},
+			want:   []string{"error transaction_type", "error ownerships"},
+		},
+		{
+			name:   "valid keychain access",
+			txType: archethic.KeychainAccessType,
+			data:   archethic.TransactionData{Ownerships: []archethic.Ownership{testOwnership(testPublicKey(1))}},
+		},
+		{
+			name:   "keychain without a JSON DID document",
+			txType: archethic.KeychainType,
+			data:   archethic.TransactionData{Content: []byte("not json"), Ownerships: []archethic.Ownership{testOwnership(testPublicKey(1))}},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "transfer without transfer nor recipient",
+			txType: archethic.TransferType,
+			want:   []string{"warning transaction_type"},
+		},
+		{
+			name:   "token with a non-JSON content",
+			txType: archethic.TokenType,
+			data:   archethic.TransactionData{Content: []byte("supply: 100")},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "token without supply",
+			txType: archethic.TokenType,
+			data:   archethic.TransactionData{Content: []byte(`{"type": "fungible"}`)},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "non-fungible token whose supply is not a number of tokens",
+			txType: archethic.TokenType,
+			data:   archethic.TransactionData{Content: []byte(`{"supply": 150000000, "type": "non-fungible"}`)},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "non-fungible token whose collection doesn't match the supply",
+			txType: archethic.TokenType,
+			data:   archethic.TransactionData{Content: []byte(`{"supply": 200000000, "type": "non-fungible", "collection": [{}]}`)},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "fungible token with a collection",
+			txType: archethic.TokenType,
+			data:   archethic.TransactionData{Content: []byte(`{"supply": 100000000, "type": "fungible", "collection": [{}]}`)},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "token of an unknown type",
+			txType: archethic.TokenType,
+			data:   archethic.TransactionData{Content: []byte(`{"supply": 100000000, "type": "other"}`)},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "valid token",
+			txType: archethic.TokenType,
+			data:   archethic.TransactionData{Content: []byte(`{"supply": 200000000, "type": "non-fungible", "collection": [{}, {}]}`)},
+		},
+		{
+			name:   "hosting without a JSON manifest",
+			txType: archethic.HostingType,
+			data:   archethic.TransactionData{Content: []byte("<html>")},
+			want:   []string{"warning content"},
+		},
+		{
+			name:   "empty data",
+			txType: archethic.DataType,
+			want:   []string{"warning content"},
+		},
+		{
+			name:   "contract without smart contract",
+			txType: archethic.ContractType,
+			want:   []string{"error smart_contract"},
+		},
+		{
+			name:   "code proposal without changes",
+			txType: archethic.CodeProposalType,
+			data:   archethic.TransactionData{Content: []byte("a new version")},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "code proposal not setting the version",
+			txType: archethic.CodeProposalType,
+			data:   archethic.TransactionData{Content: []byte("Description: fix\nChanges:\ndiff --git a/lib/node.ex b/lib/node.ex\n")},
+			want:   []string{"error content"},
+		},
+		{
+			name:   "valid code proposal",
+			txType: archethic.CodeProposalType,
+			data:   archethic.TransactionData{Content: []byte(proposal)},
+		},
+		{
+			name:   "code approval of two proposals",
+			txType: archethic.CodeApprovalType,
+			data:   archethic.TransactionData{Recipients: [][]byte{testAddress(1), testAddress(2)}},
+			want:   []string{"error recipients"},
+		},
+		{
+			name:   "unknown transaction type",
+			txType: archethic.TransactionType(42),
+			want:   []string{"error transaction_type"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transaction := &archethic.TransactionBuilder{TxType: test.txType, Data: test.data}
+			findings := ValidateTransaction(transaction)
+			var got []string
+			for _, finding := range findings {
+				got = append(got, fmt.Sprintf("%s %s", finding.Severity, finding.Field))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ValidateTransaction() = %v, want %v", findings, test.want)
+			}
+			if HasValidationErrors(findings) != strings.Contains(strings.Join(test.want, ","), SeverityError) {
+				t.Errorf("HasValidationErrors() = %v for %v", HasValidationErrors(findings), findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package tuiutils
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/archethic-foundation/archethic-cli/logging"
+	archethic "github.com/archethic-foundation/libgo"
+)
+
+// Kinds of the watch events
+const (
+	EventTransaction = "transaction"
+	EventTransfer    = "transfer"
+)
+
+// WatchTarget is a chain watched for its new transactions and the transfers it receives
+type WatchTarget struct {
+	Name    string
+	Address string
+	// deriveAddress returns the address of the chain at the given index, it is nil when the chain is not owned
+	deriveAddress func(index int) ([]byte, error)
+}
+
+// WatchEvent is a new transaction of a watched chain, or a transfer received by it
+type WatchEvent struct {
+	Target     string           `json:"target"`
+	Kind       string           `json:"kind"`
+	Type       string           `json:"type"`
+	Address    string           `json:"address,omitempty"`
+	From       string           `json:"from,omitempty"`
+	Timestamp  int64            `json:"timestamp"`
+	Transfers  []WatchTransfer  `json:"transfers,omitempty"`
+	Recipients []WatchRecipient `json:"recipients,omitempty"`
+}
+
+// WatchTransfer is an UCO or token transfer, with its amount in UCO or token units
+type WatchTransfer struct {
+	To           string `json:"to"`
+	Amount       string `json:"amount"`
+	TokenAddress string `json:"tokenAddress,omitempty"`
+	TokenId      int    `json:"tokenId,omitempty"`
+}
+
+// WatchRecipient is a smart contract called by a transaction, with the called action (empty for the legacy calls)
+type WatchRecipient struct {
+	Address string `json:"address"`
+	Action  string `json:"action,omitempty"`
+}
+
+type watchTransactionGQL struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Data    struct {
+		Ledger struct {
+			Uco struct {
+				Transfers []struct {
+					To     string `json:"to"`
+					Amount uint64 `json:"amount"`
+				} `json:"transfers"`
+			} `json:"uco"`
+			Token struct {
+				Transfers []struct {
+					To           string `json:"to"`
+					Amount       uint64 `json:"amount"`
+					TokenAddress string `json:"tokenAddress"`
+					TokenId      int    `json:"tokenId"`
+				} `json:"transfers"`
+			} `json:"token"`
+		} `json:"ledger"`
+		ActionRecipients []struct {
+			Address string `json:"address"`
+			Action  string `json:"action"`
+		} `json:"actionRecipients"`
+	} `json:"data"`
+	ValidationStamp struct {
+		Timestamp int64 `json:"timestamp"`
+	} `json:"validationStamp"`
+}
+
+type inputGQL struct {
+	From         string `json:"from"`
+	Type         string `json:"type"`
+	Amount       uint64 `json:"amount"`
+	TokenAddress string `json:"tokenAddress"`
+	TokenId      int    `json:"tokenId"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+const watchTransactionFieldsGQL = `
+	address
+	type
+	data {
+		ledger {
+			uco { transfers { to amount } }
+			token { transfers { to amount tokenAddress tokenId } }
+		}
+		actionRecipients { address action }
+	}
+	validationStamp { timestamp }
+`
+
+// AddressWatchTarget watches the chain of the address
+func AddressWatchTarget(address string) (WatchTarget, error) {
+	if _, err := hex.DecodeString(address); err != nil {
+		return WatchTarget{}, fmt.Errorf("invalid address %s: %w", address, err)
+	}
+	address = strings.ToUpper(address)
+	return WatchTarget{Name: address, Address: address}, nil
+}
+
+// SeedWatchTarget watches the chain of the seed
+func SeedWatchTarget(seed []byte, curve archethic.Curve) (WatchTarget, error) {
+	deriveAddress := func(index int) ([]byte, error) {
+		return archethic.DeriveAddress(seed, uint32(index), curve, archethic.SHA256)
+	}
+	genesisAddress, err := deriveAddress(0)
+	if err != nil {
+		return WatchTarget{}, err
+	}
+	address := strings.ToUpper(hex.EncodeToString(genesisAddress))
+	return WatchTarget{Name: "seed:" + address, Address: address, deriveAddress: deriveAddress}, nil
+}
+
+// ServiceWatchTarget watches the chain of a service of the keychain
+func ServiceWatchTarget(keychain *archethic.Keychain, serviceName string) (WatchTarget, error) {
+	if _, ok := keychain.Services[serviceName]; !ok {
+		return WatchTarget{}, errors.New("service " + serviceName + " not found in the keychain")
+	}
+	deriveAddress := func(index int) ([]byte, error) {
+		return keychain.DeriveAddress(serviceName, uint8(index))
+	}
+	genesisAddress, err := deriveAddress(0)
+	if err != nil {
+		return WatchTarget{}, err
+	}
+	return WatchTarget{Name: "service:" + serviceName, Address: strings.ToUpper(hex.EncodeToString(genesisAddress)), deriveAddress: deriveAddress}, nil
+}
+
+// watchState is the last known state of a watched chain
+type watchState struct {
+	target      WatchTarget
+	lastAddress string
+	chainLength int
+	// seenInputs are the inputs of the last poll, the ones no longer returned by the node being forgotten
+	seenInputs     map[string]bool
+	subscriptionID string
+	initialized    bool
+}
+
+// Watch reports the new transactions and the received transfers of the targets to handle, until the context is done.
+// The chains are polled at the given interval: the next transactions of the owned chains (seed or keychain service)
+// are also subscribed over the node's websocket, to be reported as soon as they are confirmed.
+func Watch(ctx context.Context, endpoint string, targets []WatchTarget, interval time.Duration, handle func(WatchEvent)) error {
+	states := make([]*watchState, len(targets))
+	for i, target := range targets {
+		states[i] = &watchState{target: target, seenInputs: map[string]bool{}}
+		_, err := states[i].poll(endpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	wake := make(chan int, len(states))
+	var rejoined <-chan struct{}
+	socket := dialWatchSocket(endpoint)
+	if socket != nil {
+		defer socket.Close()
+		rejoined = socket.Rejoined()
+	}
+	for i, state := range states {
+		state.subscribe(socket, i, wake)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var polled []int
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for i := range states {
+				polled = append(polled, i)
+			}
+		case i := <-wake:
+			polled = []int{i}
+		case <-rejoined:
+			// the subscriptions were lost with the connection: they are sent again, and the chains polled
+			// for the transactions confirmed while disconnected
+			logging.Info("websocket reconnected", "endpoint", endpoint)
+			for i, state := range states {
+				state.subscriptionID = ""
+				state.subscribe(socket, i, wake)
+				polled = append(polled, i)
+			}
+		}
+		for _, i := range polled {
+			state := states[i]
+			chainLength := state.chainLength
+			events, err := state.poll(endpoint)
+			if err != nil {
+				logging.Warn("polling failed", "target", state.target.Name, "endpoint", endpoint, "error", err)
+				continue
+			}
+			for _, event := range events {
+				handle(event)
+			}
+			// the next transaction of the chain has a new address
+			if state.chainLength != chainLength {
+				state.subscribe(socket, i, wake)
+			}
+		}
+	}
+}
+
+// dialWatchSocket connects to the websocket of the first healthy node, the watch relying on polling when it fails.
+// The connection is restored by the socket when it is lost.
+func dialWatchSocket(endpoints string) *absintheSocket {
+	endpoint, err := SelectEndpoint(endpoints)
+	if err != nil {
+		return nil
+	}
+	socket, err := dialAbsinthe(endpoint)
+	if err != nil {
+		logging.Info("websocket unavailable, polling only", "endpoint", endpoint, "error", err)
+		return nil
+	}
+	return socket
+}
+
+// subscribe waits for the confirmation of the next transaction of an owned chain
+func (s *watchState) subscribe(socket *absintheSocket, index int, wake chan<- int) {
+	if socket == nil || s.target.deriveAddress == nil {
+		return
+	}
+	if s.subscriptionID != "" {
+		socket.Unsubscribe(s.subscriptionID)
+		s.subscriptionID = ""
+	}
+	nextAddress, err := s.target.deriveAddress(s.chainLength)
+	if err != nil {
+		return
+	}
+	s.subscriptionID, err = socket.Subscribe(
+		"subscription($address: Address!) { transactionConfirmed(address: $address) { address } }",
+		map[string]interface{}{"address": hex.EncodeToString(nextAddress)},
+		func(json.RawMessage) {
+			select {
+			case wake <- index:
+			default:
+			}
+		})
+	if err != nil {
+		logging.Warn("subscription failed", "target", s.target.Name, "error", err)
+	}
+}
+
+// poll fetches the transactions and the inputs of the chain and returns the ones not reported yet.
+// The first poll records the current state of the chain without reporting it.
+func (s *watchState) poll(endpoint string) ([]WatchEvent, error) {
+	lastAddress, chainLength, err := getChainState(endpoint, s.target.Address)
+	if err != nil {
+		return nil, err
+	}
+	var events []WatchEvent
+	if s.initialized && chainLength > s.chainLength {
+		pagingAddress := ""
+		if s.chainLength > 0 {
+			pagingAddress = s.lastAddress
+		}
+		transactions, err := getTransactionsAfter(endpoint, s.target.Address, pagingAddress, chainLength-s.chainLength)
+		if err != nil {
+			return nil, err
+		}
+		for _, transaction := range transactions {
+			events = append(events, s.transactionEvent(transaction))
+		}
+	}
+	s.lastAddress, s.chainLength = lastAddress, chainLength
+
+	inputs, err := getInputs(endpoint, lastAddress)
+	if err != nil {
+		return nil, err
+	}
+	seenInputs := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		key := fmt.Sprintf("%s|%s|%s|%d|%d|%d", input.From, input.Type, input.TokenAddress, input.TokenId, input.Amount, input.Timestamp)
+		seenInputs[key] = true
+		if s.initialized && !s.seenInputs[key] {
+			events = append(events, s.transferEvent(input))
+		}
+	}
+	s.seenInputs = seenInputs
+	s.initialized = true
+	return events, nil
+}
+
+func (s *watchState) transactionEvent(transaction watchTransactionGQL) WatchEvent {
+	event := WatchEvent{
+		Target:    s.target.Name,
+		Kind:      EventTransaction,
+		Type:      transaction.Type,
+		Address:   transaction.Address,
+		Timestamp: transaction.ValidationStamp.Timestamp,
+	}
+	for _, transfer := range transaction.Data.Ledger.Uco.Transfers {
+		event.Transfers = append(event.Transfers, WatchTransfer{To: transfer.To, Amount: FormatAmount(transfer.Amount)})
+	}
+	for _, transfer := range transaction.Data.Ledger.Token.Transfers {
+		event.Transfers = append(event.Transfers, WatchTransfer{To: transfer.To, Amount: FormatAmount(transfer.Amount), TokenAddress: transfer.TokenAddress, TokenId: transfer.TokenId})
+	}
+	for _, recipient := range transaction.Data.ActionRecipients {
+		event.Recipients = append(event.Recipients, WatchRecipient{Address: recipient.Address, Action: recipient.Action})
+	}
+	return event
+}
+
+func (s *watchState) transferEvent(input inputGQL) WatchEvent {
+	event := WatchEvent{
+		Target:    s.target.Name,
+		Kind:      EventTransfer,
+		Type:      input.Type,
+		From:      input.From,
+		Timestamp: input.Timestamp,
+	}
+	if input.Type != "call" {
+		event.Transfers = []WatchTransfer{{To: s.target.Address, Amount: FormatAmount(input.Amount), TokenAddress: input.TokenAddress, TokenId: input.TokenId}}
+	}
+	return event
+}
+
+// getChainState returns the last address and the length of the chain, the address itself when the chain doesn't exist
+func getChainState(endpoint string, address string) (string, int, error) {
+	var result struct {
+		LastTransaction struct {
+			Address     string `json:"address"`
+			ChainLength int    `json:"chainLength"`
+		} `json:"lastTransaction"`
+	}
+	err := QueryNode(endpoint, "query($address: Address!) { lastTransaction(address: $address) { address chainLength } }", map[string]interface{}{"address": address}, &result)
+	if errors.Is(err, ErrChainNotFound) {
+		return address, 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return result.LastTransaction.Address, result.LastTransaction.ChainLength, nil
+}
+
+// getTransactionsAfter fetches the count transactions of the chain following the paging address (from the genesis when empty)
+func getTransactionsAfter(endpoint string, address string, pagingAddress string, count int) ([]watchTransactionGQL, error) {
+	var transactions []watchTransactionGQL
+	for len(transactions) < count {
+		var result struct {
+			TransactionChain []watchTransactionGQL `json:"transactionChain"`
+		}
+		variables := map[string]interface{}{"address": address}
+		query := fmt.Sprintf("query($address: Address!) { transactionChain(address: $address) { %s } }", watchTransactionFieldsGQL)
+		if pagingAddress != "" {
+			variables["pagingAddress"] = pagingAddress
+			query = fmt.Sprintf("query($address: Address!, $pagingAddress: Address) { transactionChain(address: $address, pagingAddress: $pagingAddress) { %s } }", watchTransactionFieldsGQL)
+		}
+		err := QueryNode(endpoint, query, variables, &result)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.TransactionChain) == 0 {
+			break
+		}
+		transactions = append(transactions, result.TransactionChain...)
+		pagingAddress = result.TransactionChain[len(result.TransactionChain)-1].Address
+	}
+	return transactions, nil
+}
+
+func getInputs(endpoint string, address string) ([]inputGQL, error) {
+	var result struct {
+		TransactionInputs []inputGQL `json:"transactionInputs"`
+	}
+	err := QueryNode(endpoint, "query($address: Address!) { transactionInputs(address: $address) { from type amount tokenAddress tokenId timestamp } }", map[string]interface{}{"address": address}, &result)
+	return result.TransactionInputs, err
+}